@@ -0,0 +1,40 @@
+package modver
+
+import "go/types"
+
+// compareAliasTypeParams compares the type parameters of obj and newObj,
+// if they are both aliases.
+// Since Go 1.24 an alias may have type parameters,
+// as in type Set[T comparable] = map[T]struct{},
+// and clients must instantiate it with type arguments that satisfy their constraints.
+// Adding or removing type parameters,
+// or tightening their constraints,
+// is a Major change;
+// relaxing their constraints is Minor.
+// The aliased types are compared separately.
+func (c *comparer) compareAliasTypeParams(obj, newObj types.Object) Result {
+	alias, ok := obj.Type().(*types.Alias)
+	if !ok {
+		return None
+	}
+	newAlias, ok := newObj.Type().(*types.Alias)
+	if !ok {
+		return None
+	}
+	r := c.compareTypeParamLists(alias.TypeParams(), newAlias.TypeParams())
+	return c.wrapf(r, TypeParamsChanged, alias, newAlias, "in type parameters of alias %s", obj.Name())
+}
+
+// typeParams gives the type parameters of typ,
+// if it is a named type or an alias,
+// and otherwise nil
+// (which is an empty list).
+func typeParams(typ types.Type) *types.TypeParamList {
+	switch typ := typ.(type) {
+	case *types.Named:
+		return typ.TypeParams()
+	case *types.Alias:
+		return typ.TypeParams()
+	}
+	return nil
+}
@@ -0,0 +1,555 @@
+package modver
+
+import "strings"
+
+// Rule is an entry in the catalog of rules that modver applies
+// to decide what version bump a change requires.
+// See Rules and LookupRule.
+type Rule struct {
+	// ID is the stable identifier of the rule.
+	// IDs of Major-level rules have the form MV1xx,
+	// Minor-level rules MV2xx,
+	// and Patchlevel rules MV3xx.
+	ID string
+
+	// Kind is the kind of Change that this rule produces.
+	// More than one rule may produce the same kind of Change,
+	// at different levels.
+	Kind ChangeKind
+
+	// Code is the version-bump level the rule requires.
+	// The level reported for a change may be lower than this,
+	// e.g. when the change is in an unexported part of the API.
+	Code ResultCode
+
+	// Title is a short description of the rule.
+	Title string
+
+	// Rationale explains why the rule requires the level it does.
+	Rationale string
+
+	// Before and After are short Go examples of an older and newer declaration
+	// that trigger the rule.
+	Before, After string
+}
+
+var catalog = []Rule{{
+	ID:        "MV101",
+	Kind:      PackageRemoved,
+	Code:      Major,
+	Title:     "Public package removed",
+	Rationale: "Clients that import the package no longer compile.",
+	Before:    "package foo // in example.com/mod/foo\n\nfunc F() {}",
+	After:     "// (no package example.com/mod/foo)",
+}, {
+	ID:        "MV102",
+	Kind:      ObjectRemoved,
+	Code:      Major,
+	Title:     "Exported object removed",
+	Rationale: "Clients that refer to the object no longer compile.",
+	Before:    "func F() {}",
+	After:     "// (no F)",
+}, {
+	ID:        "MV103",
+	Kind:      TypeKindChanged,
+	Code:      Major,
+	Title:     "Type changed kind",
+	Rationale: "Values of one kind of type (struct, slice, function, etc.) cannot be used where another kind is expected.",
+	Before:    "type T struct{ A int }",
+	After:     "type T []int",
+}, {
+	ID:        "MV104",
+	Kind:      ArrayLenChanged,
+	Code:      Major,
+	Title:     "Array length changed",
+	Rationale: "Arrays of different lengths are different types, and are not assignable to each other.",
+	Before:    "type T [4]int",
+	After:     "type T [5]int",
+}, {
+	ID:        "MV105",
+	Kind:      ChanDirChanged,
+	Code:      Major,
+	Title:     "Channel direction changed",
+	Rationale: "A send-only channel cannot be received from, and a receive-only channel cannot be sent to.",
+	Before:    "func F() <-chan int",
+	After:     "func F() chan<- int",
+}, {
+	ID:        "MV106",
+	Kind:      TypePackageChanged,
+	Code:      Major,
+	Title:     "Named type moved to another package",
+	Rationale: "Named types from different packages are different types, even when they have the same name and structure.",
+	Before:    "func F() foo.T",
+	After:     "func F() bar.T",
+}, {
+	ID:        "MV107",
+	Kind:      FieldRemoved,
+	Code:      Major,
+	Title:     "Exported struct field removed",
+	Rationale: "Clients that refer to the field, or use it in a composite literal, no longer compile.",
+	Before:    "type T struct {\n\tA int\n\tB int\n}",
+	After:     "type T struct {\n\tA int\n}",
+}, {
+	ID:        "MV108",
+	Kind:      TagValueChanged,
+	Code:      Major,
+	Title:     "Struct tag value changed",
+	Rationale: "Code that relies on the tag, such as an encoder, behaves differently.",
+	Before:    "type T struct {\n\tA int `json:\"a\"`\n}",
+	After:     "type T struct {\n\tA int `json:\"b\"`\n}",
+}, {
+	ID:        "MV109",
+	Kind:      TagRemoved,
+	Code:      Major,
+	Title:     "Struct tag key removed",
+	Rationale: "Code that relies on the tag, such as an encoder, behaves differently.",
+	Before:    "type T struct {\n\tA int `json:\"a\"`\n}",
+	After:     "type T struct {\n\tA int\n}",
+}, {
+	ID:        "MV110",
+	Kind:      InterfaceMethodsAdded,
+	Code:      Major,
+	Title:     "Method added to interface",
+	Rationale: "Client types that implemented the old interface do not implement the new one.",
+	Before:    "type I interface {\n\tM()\n}",
+	After:     "type I interface {\n\tM()\n\tN()\n}",
+}, {
+	ID:        "MV111",
+	Kind:      InterfaceIncompatible,
+	Code:      Major,
+	Title:     "Interface changed incompatibly",
+	Rationale: "The new interface does not implement the old one, so values of the new interface type cannot be used where the old one was expected.",
+	Before:    "type I interface {\n\tM()\n}",
+	After:     "type I interface {\n\tM(int)\n}",
+}, {
+	ID:        "MV112",
+	Kind:      ConstraintKindChanged,
+	Code:      Major,
+	Title:     "Interface changed between constraint and method set",
+	Rationale: "A constraint interface can be used only as a type-parameter constraint, not as the type of a value.",
+	Before:    "type I interface {\n\tM()\n}",
+	After:     "type I interface {\n\t~int\n}",
+}, {
+	ID:        "MV113",
+	Kind:      ConstraintTightened,
+	Code:      Major,
+	Title:     "Type constraint tightened",
+	Rationale: "Clients instantiating the generic type or function with a type that the new constraint excludes no longer compile.",
+	Before:    "func F[T any](T)",
+	After:     "func F[T comparable](T)",
+}, {
+	ID:        "MV114",
+	Kind:      ParamCountChanged,
+	Code:      Major,
+	Title:     "Number of parameters or results changed",
+	Rationale: "Calls with the old number of arguments, and assignments of the old number of results, no longer compile.",
+	Before:    "func F(int)",
+	After:     "func F(int, int)",
+}, {
+	ID:        "MV115",
+	Kind:      TypeParamCountChanged,
+	Code:      Major,
+	Title:     "Number of type parameters changed",
+	Rationale: "Instantiations with the old number of type arguments no longer compile.",
+	Before:    "type T[A any] struct{}",
+	After:     "type T[A, B any] struct{}",
+}, {
+	ID:        "MV116",
+	Kind:      NotAssignable,
+	Code:      Major,
+	Title:     "New type not assignable to old",
+	Rationale: "Values of the new type cannot be used where values of the old type were.",
+	Before:    "var X int",
+	After:     "var X string",
+}, {
+	ID:        "MV117",
+	Kind:      EnumValueChanged,
+	Code:      Major,
+	Title:     "Enum constant value changed",
+	Rationale: "Constants in an iota block are usually enumerations. Clients may have stored, transmitted, or switched on the old values, which now mean something else. (The level is configurable; see WithEnumLevel.)",
+	Before:    "const (\n\tRed = iota\n\tBlue\n)",
+	After:     "const (\n\tRed = iota\n\tGreen\n\tBlue\n)",
+}, {
+	ID:        "MV118",
+	Kind:      ReceiverChanged,
+	Code:      Major,
+	Title:     "Method changed from value receiver to pointer receiver",
+	Rationale: "The method is no longer in the method set of the value type, so values of the type no longer have the method, and may no longer satisfy interfaces that require it.",
+	Before:    "func (t T) M()",
+	After:     "func (t *T) M()",
+}, {
+	ID:        "MV119",
+	Kind:      PromotionRemoved,
+	Code:      Major,
+	Title:     "Promoted method or field removed",
+	Rationale: "A method or field that a type got from an embedded type is gone, e.g. because the embedded type changed or was replaced. Clients that use it, or rely on the type satisfying an interface, no longer compile.",
+	Before:    "type T struct {\n\t*bytes.Buffer\n}",
+	After:     "type T struct {\n\tbuf *bytes.Buffer\n}",
+}, {
+	ID:        "MV120",
+	Kind:      ComparabilityLost,
+	Code:      Major,
+	Title:     "Type no longer comparable",
+	Rationale: "Clients that compare values of the type with ==, or use it as a map key, no longer compile. This happens even when the change is to an unexported field, or to an embedded type.",
+	Before:    "type T struct {\n\tA int\n}",
+	After:     "type T struct {\n\tA int\n\tb []string\n}",
+}, {
+	ID:        "MV121",
+	Kind:      FieldAdded,
+	Code:      Major,
+	Title:     "Struct field added (strict profile)",
+	Rationale: "Clients that use unkeyed composite literals of the struct type no longer compile. This is so for an unexported field too, if all the struct's fields were exported. The Go 1 compatibility promise excludes such clients, so this is Major only under the strict profile.",
+	Before:    "type T struct {\n\tA int\n}",
+	After:     "type T struct {\n\tA int\n\tB int\n}",
+}, {
+	ID:        "MV122",
+	Kind:      MethodAdded,
+	Code:      Major,
+	Title:     "Method added (strict profile)",
+	Rationale: "A client type that embeds this type and another with a method of the same name has an ambiguous selector, and uses of it no longer compile. The Go 1 compatibility promise excludes such clients, so this is Major only under the strict profile.",
+	Before:    "type T struct{}",
+	After:     "type T struct{}\n\nfunc (T) M() {}",
+}, {
+	ID:        "MV123",
+	Kind:      PromotionAdded,
+	Code:      Major,
+	Title:     "Promoted method or field added (strict profile)",
+	Rationale: "As with adding a method, this can make selectors ambiguous in clients that embed the type. The Go 1 compatibility promise excludes such clients, so this is Major only under the strict profile.",
+	Before:    "type T struct {\n\tio.Reader\n}",
+	After:     "type T struct {\n\tio.ReadCloser\n}",
+}, {
+	ID:        "MV124",
+	Kind:      OptionalParamsAdded,
+	Code:      Major,
+	Title:     "Optional variadic parameters added (go1compat and strict profiles)",
+	Rationale: "Existing calls continue to compile, but clients that use the function as a value of the old function type do not. The Go team does not make such changes to the standard library.",
+	Before:    "func F(int)",
+	After:     "func F(int, ...string)",
+}, {
+	ID:        "MV125",
+	Kind:      ChanDirRestricted,
+	Code:      Major,
+	Title:     "Bidirectional channel became unidirectional (go1compat and strict profiles)",
+	Rationale: "Clients that use the channel in the direction that was removed, or that use a function as a value of the old function type, no longer compile.",
+	Before:    "func F() chan int",
+	After:     "func F() <-chan int",
+}, {
+	ID:        "MV126",
+	Kind:      ConstValueChanged,
+	Code:      Major,
+	Title:     "Constant value changed (go1compat and strict profiles)",
+	Rationale: "The Go API checker records the values of exported constants, and the standard library does not change them.",
+	Before:    "const MaxRetries = 3",
+	After:     "const MaxRetries = 5",
+}, {
+	ID:        "MV127",
+	Kind:      ObjectKindChanged,
+	Code:      Major,
+	Title:     "Object changed kind",
+	Rationale: "Each kind of object supports different uses. A variable cannot be used in a constant expression, a constant or function cannot be assigned to, and an alias is interchangeable with its target type where a defined type is not.",
+	Before:    "const Size = 16",
+	After:     "var Size = 16",
+}, {
+	ID:        "MV128",
+	Kind:      ConstTypednessChanged,
+	Code:      Major,
+	Title:     "Constant changed between typed and untyped",
+	Rationale: "An untyped constant can be used as a value of any compatible type, and a typed one cannot. A typed constant that becomes untyped keeps working in most places, but its default type (as in x := C) may change.",
+	Before:    "const C = 1",
+	After:     "const C int = 1",
+}, {
+	ID:        "MV129",
+	Kind:      TypeBecameAlias,
+	Code:      Major,
+	Title:     "Defined type became an alias (strict profile)",
+	Rationale: "The type is now identical to its target, so a client type switch with cases for both no longer compiles. This is Major only under the strict profile.",
+	Before:    "type X chan int",
+	After:     "type X = chan int",
+}, {
+	ID:        "MV130",
+	Kind:      ConstOutOfRange,
+	Code:      Major,
+	Title:     "Constant out of range",
+	Rationale: "The new value of the untyped constant is not representable by its default type, or by a type the package uses it with, so client code that uses it the same way (e.g. var x int8 = Limit) no longer compiles.",
+	Before:    "const Limit = 100",
+	After:     "const Limit = 300",
+}, {
+	ID:        "MV131",
+	Kind:      ConsumerOnlyMethodsAdded,
+	Code:      Major,
+	Title:     "Method added to a consumer-only interface (go1compat and strict profiles)",
+	Rationale: "Clients have no need to implement the interface, since it appears only in results, but those that do anyway (e.g. with test doubles) no longer compile. This is Major only under the go1compat and strict profiles.",
+	Before:    "type Iter interface {\n\tNext() bool\n}\n\nfunc Scan() Iter",
+	After:     "type Iter interface {\n\tNext() bool\n\tErr() error\n}\n\nfunc Scan() Iter",
+}, {
+	ID:        "MV132",
+	Kind:      RemovedWithoutDeprecation,
+	Code:      Major,
+	Title:     "Exported object or package removed without deprecation",
+	Rationale: "As with any removal, clients that use the object or package no longer compile, and they had no warning: it was not marked deprecated in the old version, nor was its package (for an object) or module. A removal should follow a release in which the object or package was deprecated.",
+	Before:    "func F() {}",
+	After:     "(F removed)",
+}, {
+	ID:        "MV133",
+	Kind:      TagEncodingChanged,
+	Code:      Major,
+	Title:     "Struct field encoding changed",
+	Rationale: "The change in the struct tag changes how an encoder reads and writes the field, e.g. under a different name or not at all, so data written by one version cannot be read by the other. Tags for json, yaml, xml, and protobuf are understood; see WithTagComparer.",
+	Before:    "type T struct {\n\tA int `json:\"a\"`\n}",
+	After:     "type T struct {\n\tA int `json:\"-\"`\n}",
+}, {
+	ID:        "MV201",
+	Kind:      PackageAdded,
+	Code:      Minor,
+	Title:     "Public package added",
+	Rationale: "Clients that import the new package cannot use an older version of the module.",
+	Before:    "// (no package example.com/mod/foo)",
+	After:     "package foo // in example.com/mod/foo\n\nfunc F() {}",
+}, {
+	ID:        "MV202",
+	Kind:      ObjectAdded,
+	Code:      Minor,
+	Title:     "Exported object added",
+	Rationale: "Clients that use the new object cannot use an older version of the module.",
+	Before:    "// (no F)",
+	After:     "func F() {}",
+}, {
+	ID:        "MV203",
+	Kind:      GoVersionChanged,
+	Code:      Minor,
+	Title:     "Minimum Go version raised",
+	Rationale: "Clients using an older Go toolchain cannot use the new version of the module.",
+	Before:    "go 1.21",
+	After:     "go 1.22",
+}, {
+	ID:        "MV204",
+	Kind:      ChanDirRestricted,
+	Code:      Minor,
+	Title:     "Bidirectional channel became unidirectional",
+	Rationale: "A bidirectional channel is assignable to a unidirectional one, so existing uses continue to compile.",
+	Before:    "func F() chan int",
+	After:     "func F() <-chan int",
+}, {
+	ID:        "MV205",
+	Kind:      FieldAdded,
+	Code:      Minor,
+	Title:     "Exported struct field added",
+	Rationale: "Clients that use the new field cannot use an older version of the module.",
+	Before:    "type T struct {\n\tA int\n}",
+	After:     "type T struct {\n\tA int\n\tB int\n}",
+}, {
+	ID:        "MV206",
+	Kind:      TagAdded,
+	Code:      Minor,
+	Title:     "Struct tag key added",
+	Rationale: "Clients relying on the new tag cannot use an older version of the module.",
+	Before:    "type T struct {\n\tA int\n}",
+	After:     "type T struct {\n\tA int `json:\"a\"`\n}",
+}, {
+	ID:        "MV207",
+	Kind:      SealedInterfaceMethodsAdded,
+	Code:      Minor,
+	Title:     "Method added to an interface that clients cannot implement",
+	Rationale: "The interface has unexported methods or uses internal types, so no client type can implement it and none is broken by the addition.",
+	Before:    "type I interface {\n\tM()\n\tunexp()\n}",
+	After:     "type I interface {\n\tM()\n\tN()\n\tunexp()\n}",
+}, {
+	ID:        "MV208",
+	Kind:      ConstraintRelaxed,
+	Code:      Minor,
+	Title:     "Type constraint relaxed",
+	Rationale: "Every type argument that satisfied the old constraint satisfies the new one.",
+	Before:    "func F[T comparable](T)",
+	After:     "func F[T any](T)",
+}, {
+	ID:        "MV209",
+	Kind:      OptionalParamsAdded,
+	Code:      Minor,
+	Title:     "Optional variadic parameters added",
+	Rationale: "Existing calls continue to compile, but the function can no longer be assigned to a variable of the old function type.",
+	Before:    "func F(int)",
+	After:     "func F(int, ...string)",
+}, {
+	ID:        "MV210",
+	Kind:      ReceiverChanged,
+	Code:      Minor,
+	Title:     "Method changed from pointer receiver to value receiver",
+	Rationale: "The method is still in the method set of the pointer type, and is added to that of the value type, so values of the type may satisfy more interfaces.",
+	Before:    "func (t *T) M()",
+	After:     "func (t T) M()",
+}, {
+	ID:        "MV211",
+	Kind:      PromotionAdded,
+	Code:      Minor,
+	Title:     "Promoted method or field added",
+	Rationale: "A type gained a method or field from an embedded type. Clients that use it cannot use an older version of the module.",
+	Before:    "type T struct {\n\tio.Reader\n}",
+	After:     "type T struct {\n\tio.ReadCloser\n}",
+}, {
+	ID:        "MV212",
+	Kind:      ComparabilityGained,
+	Code:      Minor,
+	Title:     "Type became comparable",
+	Rationale: "Clients that compare values of the type with ==, or use it as a map key, cannot use an older version of the module.",
+	Before:    "type T struct {\n\tA int\n\tb []string\n}",
+	After:     "type T struct {\n\tA int\n}",
+}, {
+	ID:        "MV213",
+	Kind:      MethodAdded,
+	Code:      Minor,
+	Title:     "Method added",
+	Rationale: "Clients that call the new method, or rely on the type satisfying an interface that requires it, cannot use an older version of the module.",
+	Before:    "type T struct{}",
+	After:     "type T struct{}\n\nfunc (T) M() {}",
+}, {
+	ID:        "MV214",
+	Kind:      FuncBecameVar,
+	Code:      Minor,
+	Title:     "Function became variable of function type",
+	Rationale: "Calls and uses as a function value continue to compile, and clients can now assign to it, which they cannot do with an older version of the module.",
+	Before:    "func F(int) error",
+	After:     "var F = func(int) error { ... }",
+}, {
+	ID:        "MV215",
+	Kind:      ConstTypednessChanged,
+	Code:      Minor,
+	Title:     "Typed constant became untyped",
+	Rationale: "The constant has the same default type as before, so existing uses continue to compile, and it can now be used as a value of other types.",
+	Before:    "const C int = 1",
+	After:     "const C = 1",
+}, {
+	ID:        "MV216",
+	Kind:      TypeBecameAlias,
+	Code:      Minor,
+	Title:     "Defined type became an alias",
+	Rationale: "Values of the type and its target were already assignable to each other, and now types built from them, such as []X and []chan int, are identical too. Clients that rely on that cannot use an older version of the module.",
+	Before:    "type X chan int",
+	After:     "type X = chan int",
+}, {
+	ID:        "MV217",
+	Kind:      ConsumerOnlyMethodsAdded,
+	Code:      Minor,
+	Title:     "Method added to a consumer-only interface",
+	Rationale: "The interface appears only where the module supplies values to clients, such as in function results, so clients have no need to implement it, and those that only call its methods still compile. The role can be overridden; see WithInterfaceRole.",
+	Before:    "type Iter interface {\n\tNext() bool\n}\n\nfunc Scan() Iter",
+	After:     "type Iter interface {\n\tNext() bool\n\tErr() error\n}\n\nfunc Scan() Iter",
+}, {
+	ID:        "MV218",
+	Kind:      DeprecationAdded,
+	Code:      Minor,
+	Title:     "Deprecated",
+	Rationale: "An object, package, or module was newly marked as deprecated, with a \"Deprecated:\" paragraph in its doc comment (or on the module line of go.mod). Existing clients still compile, but should move to the alternative it names before a later release removes it.",
+	Before:    "func F() {}",
+	After:     "// Deprecated: use G instead.\nfunc F() {}",
+}, {
+	ID:        "MV301",
+	Kind:      PackageRemoved,
+	Code:      Patchlevel,
+	Title:     "Non-public package removed",
+	Rationale: "The package is internal, a main package, or has no exported objects, so no client can depend on it.",
+	Before:    "package internal // in example.com/mod/internal",
+	After:     "// (no package example.com/mod/internal)",
+}, {
+	ID:        "MV302",
+	Kind:      ObjectRemoved,
+	Code:      Patchlevel,
+	Title:     "Unexported object removed",
+	Rationale: "No client can refer to the object.",
+	Before:    "func f() {}",
+	After:     "// (no f)",
+}, {
+	ID:        "MV303",
+	Kind:      NotIdentical,
+	Code:      Patchlevel,
+	Title:     "Types compatible but not identical",
+	Rationale: "The change (e.g. in unexported struct fields) does not affect clients, but the types differ.",
+	Before:    "type T struct {\n\tA int\n\tb int\n}",
+	After:     "type T struct {\n\tA int\n\tb string\n}",
+}, {
+	ID:        "MV304",
+	Kind:      ConstValueChanged,
+	Code:      Patchlevel,
+	Title:     "Constant value changed",
+	Rationale: "Clients still compile, but behave differently where they use the constant. (The level is configurable; see WithConstLevel.)",
+	Before:    "const MaxRetries = 3",
+	After:     "const MaxRetries = 5",
+}, {
+	ID:        "MV305",
+	Kind:      ImplementationChanged,
+	Code:      Patchlevel,
+	Title:     "Implementation changed",
+	Rationale: "The API is the same, but the body of a function or method, or the value or initializer of a variable or constant outside the public API, changed, and so may the behavior. Changes only to comments or formatting do not count.",
+	Before:    "func F() int { return 1 }",
+	After:     "func F() int { return 2 }",
+}, {
+	ID:        "MV306",
+	Kind:      VarInitChanged,
+	Code:      Patchlevel,
+	Title:     "Default changed",
+	Rationale: "Clients still compile, but those that rely on the exported variable's initial value, such as a default setting, behave differently. (The level is configurable; see WithVarInitLevel.)",
+	Before:    "var DefaultTimeout = 5 * time.Second",
+	After:     "var DefaultTimeout = 10 * time.Second",
+}, {
+	ID:        "MV307",
+	Kind:      TypeMoved,
+	Code:      Patchlevel,
+	Title:     "Type moved behind a forwarding alias",
+	Rationale: "The type is declared in another package now, but the alias left in its old package denotes the identical type, so clients that use the old name still compile. Changes in the moved type's shape are reported separately.",
+	Before:    "type T struct{ X int }",
+	After:     "type T = newpkg.T",
+}, {
+	ID:        "MV308",
+	Kind:      TagEncodingChanged,
+	Code:      Patchlevel,
+	Title:     "Struct tag options changed",
+	Rationale: "The change in the struct tag, such as adding omitempty, changes which values an encoder writes, but not the name or format of the field, so existing data still decodes. Reordering options is no change at all.",
+	Before:    "type T struct {\n\tA int `json:\"a\"`\n}",
+	After:     "type T struct {\n\tA int `json:\"a,omitempty\"`\n}",
+}, {
+	ID:        "MV309",
+	Kind:      FieldAdded,
+	Code:      Patchlevel,
+	Title:     "Unexported struct field added",
+	Rationale: "The struct's fields were all exported, so clients could use unkeyed composite literals of it, which now cannot list all its fields and no longer compile. The Go 1 compatibility promise excludes such clients, so this is Patchlevel (or Major under the strict profile).",
+	Before:    "type T struct {\n\tA int\n}",
+	After:     "type T struct {\n\tA int\n\tb int\n}",
+}}
+
+type ruleKey struct {
+	kind ChangeKind
+	code ResultCode
+}
+
+var (
+	rulesByID  = make(map[string]Rule)
+	rulesByKey = make(map[ruleKey]Rule)
+)
+
+func init() {
+	for _, rule := range catalog {
+		rulesByID[rule.ID] = rule
+		rulesByKey[ruleKey{kind: rule.Kind, code: rule.Code}] = rule
+	}
+}
+
+// Rules returns the catalog of rules,
+// in order by ID.
+func Rules() []Rule {
+	result := make([]Rule, len(catalog))
+	copy(result, catalog)
+	return result
+}
+
+// LookupRule returns the rule with the given ID
+// (case-insensitive),
+// and whether it exists.
+func LookupRule(id string) (Rule, bool) {
+	rule, ok := rulesByID[strings.ToUpper(id)]
+	return rule, ok
+}
+
+// ruleID gives the ID of the rule that produces a Change of the given kind at the given level,
+// or "" if there is none.
+func ruleID(kind ChangeKind, code ResultCode) string {
+	return rulesByKey[ruleKey{kind: kind, code: code}].ID
+}
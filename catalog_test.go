@@ -0,0 +1,58 @@
+package modver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	seen := make(map[ruleKey]bool)
+	for _, rule := range Rules() {
+		var prefix string
+		switch rule.Code {
+		case Major:
+			prefix = "MV1"
+		case Minor:
+			prefix = "MV2"
+		case Patchlevel:
+			prefix = "MV3"
+		}
+		if !strings.HasPrefix(rule.ID, prefix) {
+			t.Errorf("rule %s has level %s", rule.ID, rule.Code)
+		}
+		key := ruleKey{kind: rule.Kind, code: rule.Code}
+		if seen[key] {
+			t.Errorf("rule %s duplicates kind %s at level %s", rule.ID, rule.Kind, rule.Code)
+		}
+		seen[key] = true
+		if rule.Title == "" || rule.Rationale == "" || rule.Before == "" || rule.After == "" {
+			t.Errorf("rule %s is incomplete", rule.ID)
+		}
+		if got, ok := LookupRule(strings.ToLower(rule.ID)); !ok || got.ID != rule.ID {
+			t.Errorf("cannot look up rule %s", rule.ID)
+		}
+	}
+}
+
+func TestChangeRule(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "charraylen", func(olderTestDir, newerTestDir string) {
+		res, err := CompareDirs(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		changes := res.Changes()
+		if len(changes) == 0 {
+			t.Fatal("no changes")
+		}
+		if got := changes[0].Rule; got != "" {
+			t.Errorf("got rule %s for context change, want none", got)
+		}
+		if got := changes[len(changes)-1].Rule; got != "MV104" {
+			t.Errorf("got rule %s, want MV104", got)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,260 @@
+package modver
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// Change is a structured description of one link in the chain of reasons for a Result.
+// See Result.Changes.
+type Change struct {
+	// Kind classifies the change.
+	Kind ChangeKind
+
+	// Rule is the ID of the catalog rule that determined the version-bump level at this point in the chain
+	// (see LookupRule),
+	// or "" if this Change only gives context for a more-specific one.
+	Rule string
+
+	// Code is the version-bump level of the Result at this point in the chain.
+	Code ResultCode
+
+	// PkgPath is the path of the package in which the change was found.
+	PkgPath string
+
+	// Object is the name of the affected top-level object,
+	// qualified with its receiver type in the case of a method
+	// (e.g. "T.M" or "*T.M",
+	// without any type parameters of T).
+	// It is empty for changes that apply to a whole package or module.
+	Object string
+
+	// OldPos and NewPos are the source positions of the older and newer declarations
+	// (of an object, a struct field, a named type, etc.)
+	// involved in the change.
+	// Either may be the zero Position,
+	// e.g. for additions and removals.
+	OldPos, NewPos token.Position
+
+	// OldType and NewType are the older and newer types involved in the change,
+	// if any.
+	OldType, NewType string
+
+	// OldDecl and NewDecl are the source text of the older and newer declarations
+	// of the top-level object affected by the change,
+	// without doc comments or function bodies.
+	// They are set only in the outermost Change for each object,
+	// and either may be empty,
+	// e.g. for additions and removals.
+	OldDecl, NewDecl string
+
+	// Proof, if non-nil, is a client program that demonstrates the change.
+	// It is set only in the outermost Change of a Major finding,
+	// and only by Prove.
+	Proof *Proof
+
+	// Reason is a human-readable description of the change.
+	Reason string
+}
+
+// ChangeKind classifies a Change.
+type ChangeKind int
+
+// Values for ChangeKind.
+//
+// Some kinds describe a specific rule that determined a version-bump level
+// (e.g. ObjectRemoved, FieldAdded).
+// Others give the context in which a more-specific change was found
+// (e.g. ObjectChanged, ParamsChanged).
+const (
+	// OtherChange is a change that has no more-specific kind.
+	OtherChange ChangeKind = iota
+
+	// Context kinds.
+	ObjectChanged     // a top-level object changed
+	NamedTypeChanged  // the underlying type or type parameters of a named type changed
+	ElemChanged       // the element type of an array, channel, pointer, or slice changed
+	MapKeyChanged     // the key type of a map changed
+	MapElemChanged    // the element type of a map changed
+	FieldChanged      // the type of a struct field changed
+	FieldTagChanged   // the tag of a struct field changed
+	TypeParamsChanged // the type parameters of a function or alias changed
+	ParamsChanged     // the parameters of a function changed
+	ResultsChanged    // the results of a function changed
+	PromotedChanged   // the type of a method or field promoted from an embedded type changed
+
+	// Rule kinds.
+	PackageRemoved              // a package was removed
+	PackageAdded                // a package was added
+	ObjectRemoved               // a top-level object was removed
+	ObjectAdded                 // a top-level object was added
+	GoVersionChanged            // the minimum Go version in go.mod was raised
+	TypeKindChanged             // a type changed from one kind (array, struct, etc.) to another
+	ArrayLenChanged             // the length of an array type changed
+	ChanDirRestricted           // a bidirectional channel became unidirectional
+	ChanDirChanged              // the direction of a unidirectional channel changed
+	TypePackageChanged          // a named type went from one package to another
+	FieldRemoved                // an exported struct field was removed
+	FieldAdded                  // an exported struct field was added
+	TagValueChanged             // the value for a struct-tag key changed
+	TagRemoved                  // a struct-tag key was removed
+	TagAdded                    // a struct-tag key was added
+	InterfaceMethodsAdded       // methods were added to an interface
+	SealedInterfaceMethodsAdded // methods were added to an interface that only its own module can implement
+	InterfaceIncompatible       // the new interface does not implement the old one
+	ConstraintKindChanged       // an interface went from constraint to non-constraint or vice versa
+	ConstraintTightened         // a type constraint admits fewer types
+	ConstraintRelaxed           // a type constraint admits more types
+	ParamCountChanged           // the number of parameters or results changed
+	OptionalParamsAdded         // a function became variadic, adding optional parameters
+	TypeParamCountChanged       // the number of type parameters changed
+	NotAssignable               // the new type is not assignable to the old one
+	NotIdentical                // the old and new types are compatible but not identical
+	ConstValueChanged           // the value of a constant changed
+	EnumValueChanged            // the value of a constant in an enum-like iota block changed
+	PromotionRemoved            // a method or field promoted from an embedded type was removed
+	PromotionAdded              // a method or field promoted from an embedded type was added
+	ComparabilityLost           // a type that was comparable is no longer
+	ComparabilityGained         // a type that was not comparable now is
+	ReceiverChanged             // a method's receiver changed between pointer and value
+	ImplementationChanged       // the body of a function, or the initializer of a variable or constant outside the public API, changed
+	MethodAdded                 // a method was added to a non-interface type
+	ObjectKindChanged           // a top-level object changed kind, e.g. from constant to variable
+	FuncBecameVar               // a function became a variable of function type
+	ConstTypednessChanged       // a constant changed between typed and untyped
+	TypeBecameAlias             // a defined type became an alias for an unnamed type
+	VarInitChanged              // the initializer of an exported variable changed
+	ConstOutOfRange             // the value of an untyped constant is no longer representable by a type it is used with
+	TypeMoved                   // a defined type moved to another package, leaving a forwarding alias
+	ConsumerOnlyMethodsAdded    // methods were added to an interface that clients only consume
+	DeprecationAdded            // an object, package, or module was newly marked as deprecated
+	RemovedWithoutDeprecation   // a top-level object or public package was removed without having been deprecated
+	TagEncodingChanged          // a struct tag value changed, as understood by a TagComparer
+	numChangeKinds
+)
+
+var changeKindNames = [numChangeKinds]string{
+	OtherChange:                 "OtherChange",
+	ObjectChanged:               "ObjectChanged",
+	NamedTypeChanged:            "NamedTypeChanged",
+	ElemChanged:                 "ElemChanged",
+	MapKeyChanged:               "MapKeyChanged",
+	MapElemChanged:              "MapElemChanged",
+	FieldChanged:                "FieldChanged",
+	FieldTagChanged:             "FieldTagChanged",
+	TypeParamsChanged:           "TypeParamsChanged",
+	ParamsChanged:               "ParamsChanged",
+	ResultsChanged:              "ResultsChanged",
+	PromotedChanged:             "PromotedChanged",
+	PackageRemoved:              "PackageRemoved",
+	PackageAdded:                "PackageAdded",
+	ObjectRemoved:               "ObjectRemoved",
+	ObjectAdded:                 "ObjectAdded",
+	GoVersionChanged:            "GoVersionChanged",
+	TypeKindChanged:             "TypeKindChanged",
+	ArrayLenChanged:             "ArrayLenChanged",
+	ChanDirRestricted:           "ChanDirRestricted",
+	ChanDirChanged:              "ChanDirChanged",
+	TypePackageChanged:          "TypePackageChanged",
+	FieldRemoved:                "FieldRemoved",
+	FieldAdded:                  "FieldAdded",
+	TagValueChanged:             "TagValueChanged",
+	TagRemoved:                  "TagRemoved",
+	TagAdded:                    "TagAdded",
+	InterfaceMethodsAdded:       "InterfaceMethodsAdded",
+	SealedInterfaceMethodsAdded: "SealedInterfaceMethodsAdded",
+	InterfaceIncompatible:       "InterfaceIncompatible",
+	ConstraintKindChanged:       "ConstraintKindChanged",
+	ConstraintTightened:         "ConstraintTightened",
+	ConstraintRelaxed:           "ConstraintRelaxed",
+	ParamCountChanged:           "ParamCountChanged",
+	OptionalParamsAdded:         "OptionalParamsAdded",
+	TypeParamCountChanged:       "TypeParamCountChanged",
+	NotAssignable:               "NotAssignable",
+	NotIdentical:                "NotIdentical",
+	ConstValueChanged:           "ConstValueChanged",
+	EnumValueChanged:            "EnumValueChanged",
+	PromotionRemoved:            "PromotionRemoved",
+	PromotionAdded:              "PromotionAdded",
+	ComparabilityLost:           "ComparabilityLost",
+	ComparabilityGained:         "ComparabilityGained",
+	ReceiverChanged:             "ReceiverChanged",
+	ImplementationChanged:       "ImplementationChanged",
+	MethodAdded:                 "MethodAdded",
+	ObjectKindChanged:           "ObjectKindChanged",
+	FuncBecameVar:               "FuncBecameVar",
+	ConstTypednessChanged:       "ConstTypednessChanged",
+	TypeBecameAlias:             "TypeBecameAlias",
+	VarInitChanged:              "VarInitChanged",
+	ConstOutOfRange:             "ConstOutOfRange",
+	TypeMoved:                   "TypeMoved",
+	ConsumerOnlyMethodsAdded:    "ConsumerOnlyMethodsAdded",
+	DeprecationAdded:            "DeprecationAdded",
+	RemovedWithoutDeprecation:   "RemovedWithoutDeprecation",
+	TagEncodingChanged:          "TagEncodingChanged",
+}
+
+// String returns the name of k.
+func (k ChangeKind) String() string {
+	if k < 0 || k >= numChangeKinds {
+		return "unknown ChangeKind value"
+	}
+	return changeKindNames[k]
+}
+
+// wrapf is like rwrapf but also records a Change of the given kind.
+// Each of older and newer may be a types.Object, a types.Type, or nil.
+// They supply the positions and type strings of the Change.
+func (c *comparer) wrapf(r Result, kind ChangeKind, older, newer any, format string, args ...any) Result {
+	if r.Code() == None {
+		return r
+	}
+	ch := Change{Kind: kind, Rule: ruleID(kind, r.Code())}
+	ch.OldPos, ch.OldType = describe(c.olderFset, older)
+	ch.NewPos, ch.NewType = describe(c.newerFset, newer)
+	return wrapped{r: r, change: ch, whyfmt: format, whyargs: args}
+}
+
+// wrapk is like rwrapf but also records a Change of the given kind,
+// for changes that involve no particular types.
+func wrapk(r Result, kind ChangeKind, format string, args ...any) Result {
+	if r.Code() == None {
+		return r
+	}
+	return wrapped{r: r, change: Change{Kind: kind, Rule: ruleID(kind, r.Code())}, whyfmt: format, whyargs: args}
+}
+
+// inObject records in r (if it is a wrapper) the package path and name of the top-level object it concerns.
+func inObject(r Result, pkgPath, id string) Result {
+	if w, ok := r.(wrapped); ok {
+		w.change.PkgPath, w.change.Object = pkgPath, id
+		return w
+	}
+	return r
+}
+
+func describe(fset *token.FileSet, x any) (pos token.Position, typ string) {
+	var obj types.Object
+
+	switch x := x.(type) {
+	case types.Object:
+		obj = x
+		typ = x.Type().String()
+	case *types.Named:
+		obj = x.Obj()
+		typ = x.String()
+	case *types.Alias:
+		obj = x.Obj()
+		typ = x.String()
+	case *types.TypeParam:
+		obj = x.Obj()
+		typ = x.String()
+	case types.Type:
+		typ = x.String()
+	}
+
+	if obj != nil && fset != nil {
+		pos = fset.Position(obj.Pos())
+	}
+	return pos, typ
+}
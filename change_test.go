@@ -0,0 +1,59 @@
+package modver
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "chfield", func(olderTestDir, newerTestDir string) {
+		res, err := CompareDirs(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changes := res.Changes()
+		wantKinds := []ChangeKind{ObjectChanged, FieldChanged, NotAssignable}
+		if len(changes) != len(wantKinds) {
+			t.Fatalf("got %d changes, want %d: %v", len(changes), len(wantKinds), changes)
+		}
+		for i, ch := range changes {
+			if ch.Kind != wantKinds[i] {
+				t.Errorf("change %d: got kind %s, want %s", i, ch.Kind, wantKinds[i])
+			}
+			if ch.Code != Major {
+				t.Errorf("change %d: got code %s, want Major", i, ch.Code)
+			}
+			if ch.PkgPath != "chfield" {
+				t.Errorf("change %d: got package path %s, want chfield", i, ch.PkgPath)
+			}
+			if ch.Object != "X" {
+				t.Errorf("change %d: got object %s, want X", i, ch.Object)
+			}
+		}
+
+		if got := changes[0].OldPos; filepath.Base(got.Filename) != "x.go" || got.Line != 4 {
+			t.Errorf("got old position %s, want x.go:4", got)
+		}
+		if got := changes[1].NewPos; got.Line != 5 {
+			t.Errorf("got new field position %s, want line 5", got)
+		}
+		if got := changes[2].NewType; got != "string" {
+			t.Errorf("got new type %s, want string", got)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangeKindString(t *testing.T) {
+	for k := OtherChange; k < numChangeKinds; k++ {
+		if k.String() == "" {
+			t.Errorf("ChangeKind %d has no name", k)
+		}
+	}
+	if got := numChangeKinds.String(); got != "unknown ChangeKind value" {
+		t.Errorf("got %s for out-of-range ChangeKind", got)
+	}
+}
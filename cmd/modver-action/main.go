@@ -26,7 +26,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Creating GitHub client: %s", err)
 	}
-	result, err := internal.PR(ctx, gh, owner, reponame, prnum)
+	result, err := internal.PR(ctx, gh, owner, reponame, prnum, modver.CompareGit2)
 	if err != nil {
 		log.Fatalf("Running comparison: %s", err)
 	}
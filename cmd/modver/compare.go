@@ -12,47 +12,105 @@ import (
 	"github.com/bobg/modver/v2/internal"
 )
 
-func doCompare(ctx context.Context, opts options) (modver.Result, error) {
-	return doCompareHelper(ctx, opts, internal.NewClient, internal.PR, modver.CompareGitWith, modver.CompareDirs)
+// The usage of each comparison mode,
+// after the command name.
+// These must match the usage in the package doc comment
+// (see TestUsage).
+const (
+	compareFlagsUsage = "[-all] [-profile PROFILE] [-constlevel LEVEL] [-enumlevel LEVEL] [-varinitlevel LEVEL] [-interfacerole PKGPATH.TYPE=ROLE ...] [-prove] [-summary]"
+
+	prUsage   = "-pr URL [-token GITHUB_TOKEN] " + compareFlagsUsage + " [-format FORMAT] [-threshold LEVEL]"
+	gitUsage  = "-git REPO [-gitcmd GIT_COMMAND] " + compareFlagsUsage + " [-q | -format FORMAT] [-threshold LEVEL | -v1 OLDERVERSION -v2 NEWERVERSION | -versions] OLDERREV NEWERREV"
+	dirsUsage = compareFlagsUsage + " [-q | -format FORMAT] [-threshold LEVEL | -v1 OLDERVERSION -v2 NEWERVERSION] OLDERDIR NEWERDIR"
+)
+
+func doCompare(ctx context.Context, opts *options) (modver.Result, error) {
+	compareOpts, err := opts.compareOptions()
+	if err != nil {
+		return modver.None, err
+	}
+
+	compareDirs := modver.CompareDirs
+	switch {
+	case opts.format == "junit" || opts.prove || opts.summary || len(compareOpts) > 0:
+		compareDirs = func(older, newer string) (modver.Result, error) {
+			olders, newers, err := modver.LoadDirs(older, newer)
+			if err != nil {
+				return modver.None, err
+			}
+			if opts.format == "junit" {
+				opts.objects = mergeObjects(modver.ExportedObjects(olders), modver.ExportedObjects(newers))
+			}
+			var res modver.Result
+			if opts.all {
+				res = modver.CompareAll(olders, newers, compareOpts...)
+			} else {
+				res = modver.Compare(olders, newers, compareOpts...)
+			}
+			if opts.prove {
+				res = modver.Prove(olders, newers, res)
+			}
+			if opts.summary {
+				opts.pkgSummary = modver.Summarize(olders, newers, res)
+			}
+			return res, nil
+		}
+	case opts.all:
+		compareDirs = modver.CompareDirsAll
+	}
+	return doCompareHelper(ctx, opts, internal.NewClient, internal.PR, modver.CompareGitWith, compareDirs)
 }
 
 type (
 	newClientType      = func(ctx context.Context, host, token string) (*github.Client, error)
-	prType             = func(ctx context.Context, gh *github.Client, owner, reponame string, prnum int) (modver.Result, error)
+	prType             = func(ctx context.Context, gh *github.Client, owner, reponame string, prnum int, comparer func(ctx context.Context, baseURL, baseSHA, headURL, headSHA string) (modver.Result, error)) (modver.Result, error)
 	compareGitWithType = func(ctx context.Context, repoURL, olderRev, newerRev string, f func(older, newer string) (modver.Result, error)) (modver.Result, error)
 	compareDirsType    = func(older, newer string) (modver.Result, error)
 )
 
-func doCompareHelper(ctx context.Context, opts options, newClient newClientType, pr prType, compareGitWith compareGitWithType, compareDirs compareDirsType) (modver.Result, error) {
+func doCompareHelper(ctx context.Context, opts *options, newClient newClientType, pr prType, compareGitWith compareGitWithType, compareDirs compareDirsType) (modver.Result, error) {
+	if compareDirs != nil {
+		// Record the directories that are compared, for reporting.
+		inner := compareDirs
+		compareDirs = func(older, newer string) (modver.Result, error) {
+			opts.olderDir, opts.newerDir = older, newer
+			return inner(older, newer)
+		}
+	}
+
 	if opts.pr != "" {
 		host, owner, reponame, prnum, err := internal.ParsePR(opts.pr)
 		if err != nil {
 			return modver.None, errors.Wrap(err, "parsing pull-request URL")
 		}
 		if opts.ghtoken == "" {
-			return modver.None, fmt.Errorf("usage: %s -pr URL [-token TOKEN]", os.Args[0])
+			return modver.None, fmt.Errorf("usage: %s %s", os.Args[0], prUsage)
 		}
 		gh, err := newClient(ctx, host, opts.ghtoken)
 		if err != nil {
 			return modver.None, errors.Wrap(err, "creating GitHub client")
 		}
-		return pr(ctx, gh, owner, reponame, prnum)
+		comparer := func(ctx context.Context, baseURL, baseSHA, headURL, headSHA string) (modver.Result, error) {
+			opts.prBase, opts.prHead = baseSHA, headSHA
+			return modver.CompareGit2With(ctx, baseURL, baseSHA, headURL, headSHA, compareDirs)
+		}
+		return pr(ctx, gh, owner, reponame, prnum, comparer)
 	}
 
 	if opts.gitRepo != "" {
 		if len(opts.args) != 2 {
-			return nil, fmt.Errorf("usage: %s -git REPO [-gitcmd GIT_COMMAND] [-q | -pretty] [-v1 OLDERVERSION -v2 NEWERVERSION | -versions] OLDERREV NEWERREV", os.Args[0])
+			return nil, fmt.Errorf("usage: %s %s", os.Args[0], gitUsage)
 		}
 
-		callback := modver.CompareDirs
+		callback := compareDirs
 		if opts.versions {
-			callback = getTags(&opts.v1, &opts.v2, opts.args[0], opts.args[1])
+			callback = getTagsHelper(&opts.v1, &opts.v2, opts.args[0], opts.args[1], compareDirs)
 		}
 
 		return compareGitWith(ctx, opts.gitRepo, opts.args[0], opts.args[1], callback)
 	}
 	if len(opts.args) != 2 {
-		return nil, fmt.Errorf("usage: %s [-q | -pretty] [-v1 OLDERVERSION -v2 NEWERVERSION] OLDERDIR NEWERDIR", os.Args[0])
+		return nil, fmt.Errorf("usage: %s %s", os.Args[0], dirsUsage)
 	}
 	return compareDirs(opts.args[0], opts.args[1])
 }
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-github/v50/github"
@@ -78,7 +80,7 @@ func TestDoCompare(t *testing.T) {
 				compareDirs = tc.compareDirs(t, &calls)
 			}
 
-			_, err := doCompareHelper(ctx, tc.opts, mockNewClient, pr, compareGitWith, compareDirs)
+			_, err := doCompareHelper(ctx, &tc.opts, mockNewClient, pr, compareGitWith, compareDirs)
 			if err != nil {
 				if !tc.wantErr {
 					t.Errorf("got error %s, wanted none", err)
@@ -102,7 +104,7 @@ func mockNewClient(ctx context.Context, host, token string) (*github.Client, err
 
 func mockPR(wantOwner, wantRepo string, wantPRNum int) func(*testing.T, *int) prType {
 	return func(t *testing.T, calls *int) prType {
-		return func(ctx context.Context, gh *github.Client, owner, reponame string, prnum int) (modver.Result, error) {
+		return func(ctx context.Context, gh *github.Client, owner, reponame string, prnum int, comparer func(ctx context.Context, baseURL, baseSHA, headURL, headSHA string) (modver.Result, error)) (modver.Result, error) {
 			*calls++
 			if owner != wantOwner {
 				t.Errorf("got owner %s, want %s", owner, wantOwner)
@@ -150,3 +152,15 @@ func mockCompareDirs(wantOlder, wantNewer string) func(*testing.T, *int) compare
 		}
 	}
 }
+
+func TestUsage(t *testing.T) {
+	doc, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, usage := range []string{prUsage, gitUsage, dirsUsage} {
+		if line := "//\tmodver " + usage + "\n"; !strings.Contains(string(doc), line) {
+			t.Errorf("usage %q is not in the doc comment in main.go", usage)
+		}
+	}
+}
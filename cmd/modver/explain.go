@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bobg/modver/v2"
+)
+
+// doExplain writes the catalog entries for the rules with the given IDs to out,
+// or a one-line summary of every rule if there are no IDs.
+func doExplain(out io.Writer, ids []string) error {
+	if len(ids) == 0 {
+		for _, rule := range modver.Rules() {
+			fmt.Fprintf(out, "%s  %-10s  %s\n", rule.ID, rule.Code, rule.Title)
+		}
+		return nil
+	}
+
+	for i, id := range ids {
+		rule, ok := modver.LookupRule(id)
+		if !ok {
+			return fmt.Errorf("unknown rule %s", id)
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		writeRule(out, rule)
+	}
+	return nil
+}
+
+func writeRule(out io.Writer, rule modver.Rule) {
+	fmt.Fprintf(out, "%s: %s\n", rule.ID, rule.Title)
+	fmt.Fprintf(out, "Level: %s\n", rule.Code)
+	fmt.Fprintf(out, "Kind: %s\n", rule.Kind)
+	fmt.Fprintf(out, "\n%s\n", rule.Rationale)
+	fmt.Fprintf(out, "\nBefore:\n%s\n", indent(rule.Before))
+	fmt.Fprintf(out, "\nAfter:\n%s\n", indent(rule.After))
+}
+
+func indent(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = "\t" + line
+	}
+	return strings.Join(lines, "\n")
+}
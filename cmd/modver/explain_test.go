@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDoExplain(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := doExplain(buf, []string{"mv104"}); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.HasPrefix(got, "MV104: Array length changed\nLevel: Major\n") {
+		t.Errorf("got %s", got)
+	}
+	if !strings.Contains(got, "\ttype T [5]int\n") {
+		t.Errorf("no example in %s", got)
+	}
+
+	buf.Reset()
+	if err := doExplain(buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "MV303") {
+		t.Errorf("rule summary is missing MV303: %s", buf)
+	}
+
+	if err := doExplain(buf, []string{"MV999"}); err == nil {
+		t.Error("got no error for unknown rule")
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/bobg/modver/v2"
+)
+
+// jsonVersion is the version of the JSON document format written by writeJSON.
+const jsonVersion = 1
+
+type (
+	jsonDoc struct {
+		Version  int            `json:"version"`
+		Mode     string         `json:"mode"`
+		Repo     string         `json:"repo,omitempty"`
+		PR       string         `json:"pr,omitempty"`
+		Older    string         `json:"older"`
+		Newer    string         `json:"newer"`
+		Versions *jsonVersions  `json:"versions,omitempty"`
+		Result   modver.Results `json:"result"`
+	}
+
+	jsonVersions struct {
+		Older string `json:"older"`
+		Newer string `json:"newer"`
+		OK    bool   `json:"ok"`
+	}
+)
+
+// writeJSON writes a JSON document describing the comparison and its result to out.
+// If ok is non-nil,
+// it is the outcome of checking the -v1 and -v2 versions against the result.
+func writeJSON(out io.Writer, res modver.Result, opts options, ok *bool) error {
+	doc := jsonDoc{
+		Version: jsonVersion,
+		Result:  toResults(res),
+	}
+
+	switch {
+	case opts.pr != "":
+		doc.Mode = "pr"
+		doc.PR = opts.pr
+		doc.Older, doc.Newer = opts.prBase, opts.prHead
+
+	case opts.gitRepo != "":
+		doc.Mode = "git"
+		doc.Repo = opts.gitRepo
+		doc.Older, doc.Newer = opts.args[0], opts.args[1]
+
+	default:
+		doc.Mode = "dirs"
+		doc.Older, doc.Newer = opts.args[0], opts.args[1]
+	}
+
+	if ok != nil {
+		doc.Versions = &jsonVersions{Older: opts.v1, Newer: opts.v2, OK: *ok}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func toResults(res modver.Result) modver.Results {
+	if rs, ok := res.(modver.Results); ok {
+		return rs
+	}
+	if res.Code() == modver.None {
+		return modver.Results{}
+	}
+	return modver.Results{res}
+}
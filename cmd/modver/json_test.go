@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bobg/modver/v2"
+)
+
+func TestWriteJSON(t *testing.T) {
+	cases := []struct {
+		res          modver.Result
+		opts         options
+		wantExitCode int
+		want         jsonDoc
+	}{{
+		res:  modver.Minor,
+		opts: options{args: []string{"a", "b"}},
+		want: jsonDoc{
+			Version: jsonVersion,
+			Mode:    "dirs",
+			Older:   "a",
+			Newer:   "b",
+			Result:  modver.Results{modver.Minor},
+		},
+	}, {
+		res:  modver.None,
+		opts: options{gitRepo: ".git", args: []string{"HEAD~1", "HEAD"}},
+		want: jsonDoc{
+			Version: jsonVersion,
+			Mode:    "git",
+			Repo:    ".git",
+			Older:   "HEAD~1",
+			Newer:   "HEAD",
+			Result:  modver.Results{},
+		},
+	}, {
+		res:  modver.Major,
+		opts: options{pr: "https://github.com/foo/bar/pull/17", prBase: "base", prHead: "head"},
+		want: jsonDoc{
+			Version: jsonVersion,
+			Mode:    "pr",
+			PR:      "https://github.com/foo/bar/pull/17",
+			Older:   "base",
+			Newer:   "head",
+			Result:  modver.Results{modver.Major},
+		},
+	}, {
+		res:          modver.Major,
+		opts:         options{v1: "v1.0.0", v2: "v1.1.0", args: []string{"a", "b"}},
+		wantExitCode: 1,
+		want: jsonDoc{
+			Version:  jsonVersion,
+			Mode:     "dirs",
+			Older:    "a",
+			Newer:    "b",
+			Versions: &jsonVersions{Older: "v1.0.0", Newer: "v1.1.0", OK: false},
+			Result:   modver.Results{modver.Major},
+		},
+	}}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case_%02d", i+1), func(t *testing.T) {
+			tc.opts.format = "json"
+
+			buf := new(bytes.Buffer)
+			exitCode := doShowResult(buf, tc.res, tc.opts)
+			if exitCode != tc.wantExitCode {
+				t.Errorf("got exit code %d, want %d", exitCode, tc.wantExitCode)
+			}
+
+			var got jsonDoc
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
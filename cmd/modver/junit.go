@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bobg/modver/v2"
+)
+
+// Types for a JUnit XML report.
+// Only the parts used by modver are included.
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr,omitempty"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// junitThreshold is the default highest level of change that does not fail a JUnit test case.
+const junitThreshold = modver.Minor
+
+// writeJUnit writes res to out as a JUnit report,
+// with one test suite per package and one test case per exported object
+// (as recorded in opts.objects),
+// plus one for each change to any other object,
+// such as an exported method of an unexported type,
+// or a change to a whole package with no exported objects.
+func writeJUnit(out io.Writer, res modver.Result, opts options) error {
+	allowed, err := allowedLevel(opts)
+	if err != nil {
+		return err
+	}
+
+	var (
+		// Results for top-level objects, and for whole packages, keyed by package path.
+		objResults = make(map[string]map[string]modver.Result)
+		pkgResults = make(map[string]modver.Result)
+
+		// Results for the module as a whole.
+		modResults []modver.Result
+	)
+	for _, r := range toResults(res) {
+		changes := r.Changes()
+		if len(changes) == 0 {
+			continue
+		}
+		switch ch := changes[0]; {
+		case ch.PkgPath == "":
+			modResults = append(modResults, r)
+		case ch.Object == "":
+			pkgResults[ch.PkgPath] = r
+		default:
+			if objResults[ch.PkgPath] == nil {
+				objResults[ch.PkgPath] = make(map[string]modver.Result)
+			}
+			objResults[ch.PkgPath][ch.Object] = r
+		}
+	}
+
+	suites := junitTestSuites{Name: "modver"}
+
+	if len(modResults) > 0 {
+		suite := junitTestSuite{Name: modulePkg}
+		for _, r := range modResults {
+			name := r.Changes()[0].Kind.String()
+			suite.Cases = append(suite.Cases, junitCase(name, modulePkg, r, allowed))
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	var pkgPaths []string
+	for pkgPath := range opts.objects {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	for pkgPath := range objResults {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	for pkgPath := range pkgResults {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	slices.Sort(pkgPaths)
+	pkgPaths = slices.Compact(pkgPaths)
+
+	for _, pkgPath := range pkgPaths {
+		suite := junitTestSuite{Name: pkgPath}
+		ids := opts.objects[pkgPath]
+		for _, id := range ids {
+			r, ok := objResults[pkgPath][id]
+			if !ok {
+				// A change to the whole package (such as its removal) applies to each of its objects.
+				r, ok = pkgResults[pkgPath]
+			}
+			if !ok {
+				r = modver.None
+			}
+			suite.Cases = append(suite.Cases, junitCase(id, pkgPath, r, allowed))
+		}
+
+		// Changes to objects that clients can reach but that are not exported objects of public packages,
+		// such as exported methods of unexported types and types in internal packages.
+		var others []string
+		for id := range objResults[pkgPath] {
+			if !slices.Contains(ids, id) {
+				others = append(others, id)
+			}
+		}
+		slices.Sort(others)
+		for _, id := range others {
+			suite.Cases = append(suite.Cases, junitCase(id, pkgPath, objResults[pkgPath][id], allowed))
+		}
+
+		if r, ok := pkgResults[pkgPath]; ok && len(ids) == 0 {
+			// A change to a package with no exported objects of its own.
+			suite.Cases = append(suite.Cases, junitCase(r.Changes()[0].Kind.String(), pkgPath, r, allowed))
+		}
+
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	for i := range suites.Suites {
+		suite := &suites.Suites[i]
+		suite.Tests = len(suite.Cases)
+		for _, c := range suite.Cases {
+			if c.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+func junitCase(name, className string, r modver.Result, allowed modver.ResultCode) junitTestCase {
+	c := junitTestCase{Name: name, ClassName: className}
+	if r.Code() <= allowed {
+		return c
+	}
+
+	buf := new(bytes.Buffer)
+	modver.PrettyDecls(buf, r)
+
+	var ruleID string
+	if changes := r.Changes(); len(changes) > 0 {
+		ruleID = changes[len(changes)-1].Rule
+	}
+
+	c.Failure = &junitFailure{
+		Message: fmt.Sprintf("%s change exceeds allowed level %s", r.Code(), allowed),
+		Type:    ruleID,
+		Text:    buf.String(),
+	}
+	return c
+}
+
+// allowedLevel gives the highest level of change that does not fail a JUnit test case.
+// It is the highest level for which the -v1 and -v2 versions are adequate, if they are given,
+// otherwise the -threshold level
+// (which parseArgs does not allow with -v1 and -v2),
+// otherwise junitThreshold.
+func allowedLevel(opts options) (modver.ResultCode, error) {
+	if opts.v1 != "" && opts.v2 != "" {
+		for code := modver.Major; code > modver.None; code-- {
+			if versionsOK(code, opts.v1, opts.v2) {
+				return code, nil
+			}
+		}
+		return modver.None, nil
+	}
+	if opts.threshold != "" {
+		var code modver.ResultCode
+		err := code.UnmarshalText([]byte(opts.threshold))
+		return code, err
+	}
+	return junitThreshold, nil
+}
+
+// mergeObjects merges the per-package lists of object names in a and b.
+func mergeObjects(a, b map[string][]string) map[string][]string {
+	result := make(map[string][]string)
+	for _, m := range []map[string][]string{a, b} {
+		for pkgPath, ids := range m {
+			result[pkgPath] = append(result[pkgPath], ids...)
+		}
+	}
+	for pkgPath, ids := range result {
+		slices.Sort(ids)
+		result[pkgPath] = slices.Compact(ids)
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bobg/modver/v2"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	const (
+		older = "../../testdata/_transitivemajor/a1"
+		newer = "../../testdata/_transitivemajor/a2"
+	)
+
+	olders, newers, err := modver.LoadDirs(older, newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := modver.CompareAll(olders, newers)
+	objects := mergeObjects(modver.ExportedObjects(olders), modver.ExportedObjects(newers))
+
+	cases := []struct {
+		opts         options
+		wantFailures int
+	}{{
+		opts:         options{format: "junit"},
+		wantFailures: 1,
+	}, {
+		opts:         options{format: "junit", threshold: "Major"},
+		wantFailures: 0,
+	}, {
+		opts:         options{format: "junit", v1: "v1.2.3", v2: "v2.0.0"},
+		wantFailures: 0,
+	}, {
+		opts:         options{format: "junit", v1: "v1.2.3", v2: "v1.3.0"},
+		wantFailures: 1,
+	}}
+
+	for _, tc := range cases {
+		tc.opts.args = []string{older, newer}
+		tc.opts.objects = objects
+
+		buf := new(bytes.Buffer)
+		if err := writeJUnit(buf, res, tc.opts); err != nil {
+			t.Fatal(err)
+		}
+
+		var got junitTestSuites
+		if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Tests != 1 || len(got.Suites) != 1 {
+			t.Fatalf("got %d tests in %d suites, want 1 in 1", got.Tests, len(got.Suites))
+		}
+		if got.Failures != tc.wantFailures {
+			t.Errorf("with %+v, got %d failures, want %d", tc.opts, got.Failures, tc.wantFailures)
+		}
+
+		suite := got.Suites[0]
+		if suite.Name != "foo.bar/a" || suite.Cases[0].Name != "F" {
+			t.Errorf("got suite %s with test case %s, want foo.bar/a with F", suite.Name, suite.Cases[0].Name)
+		}
+		if f := suite.Cases[0].Failure; f != nil && f.Text == "" {
+			t.Error("failure has no text")
+		}
+	}
+}
+
+func TestWriteJUnitReachable(t *testing.T) {
+	// Exported methods of unexported types are not in the list of exported objects,
+	// but clients can call them on values they get from New.
+	const (
+		gomod = "module foo.bar/r\n\ngo 1.18\n"
+		older = "package r\n\ntype impl struct{}\n\nfunc (impl) Get() int { return 0 }\n\nfunc New() impl { return impl{} }\n"
+		newer = "package r\n\ntype impl struct{}\n\nfunc New() impl { return impl{} }\n"
+	)
+
+	tmpdir := t.TempDir()
+	for dir, src := range map[string]string{"older": older, "newer": newer} {
+		dir = filepath.Join(tmpdir, dir)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "r.go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	olders, newers, err := modver.LoadDirs(filepath.Join(tmpdir, "older"), filepath.Join(tmpdir, "newer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := modver.CompareAll(olders, newers)
+
+	opts := options{format: "junit", objects: mergeObjects(modver.ExportedObjects(olders), modver.ExportedObjects(newers))}
+	buf := new(bytes.Buffer)
+	if err := writeJUnit(buf, res, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Failures != 1 {
+		t.Fatalf("got %d failures, want 1", got.Failures)
+	}
+	var names []string
+	for _, c := range got.Suites[0].Cases {
+		names = append(names, c.Name)
+		if c.Name == "impl.Get" && c.Failure == nil {
+			t.Error("impl.Get did not fail")
+		}
+	}
+	if !slices.Contains(names, "impl.Get") {
+		t.Errorf("got test cases %v, want one for impl.Get", names)
+	}
+}
+
+func TestMergeObjects(t *testing.T) {
+	got := mergeObjects(
+		map[string][]string{"a": {"X", "Y"}, "b": {"Z"}},
+		map[string][]string{"a": {"W", "X"}},
+	)
+	if len(got) != 2 || len(got["a"]) != 3 || got["a"][0] != "W" || len(got["b"]) != 1 {
+		t.Errorf("got %v", got)
+	}
+}
@@ -5,9 +5,41 @@
 //
 // Usage:
 //
-//	modver -pr URL [-token GITHUB_TOKEN]
-//	modver -git REPO [-gitcmd GIT_COMMAND] [-q | -pretty] [-v1 OLDERVERSION -v2 NEWERVERSION | -versions] OLDERREV NEWERREV
-//	modver [-q | -pretty] [-v1 OLDERVERSION -v2 NEWERVERSION] OLDERDIR NEWERDIR
+//	modver -pr URL [-token GITHUB_TOKEN] [-all] [-profile PROFILE] [-constlevel LEVEL] [-enumlevel LEVEL] [-varinitlevel LEVEL] [-interfacerole PKGPATH.TYPE=ROLE ...] [-prove] [-summary] [-format FORMAT] [-threshold LEVEL]
+//	modver -git REPO [-gitcmd GIT_COMMAND] [-all] [-profile PROFILE] [-constlevel LEVEL] [-enumlevel LEVEL] [-varinitlevel LEVEL] [-interfacerole PKGPATH.TYPE=ROLE ...] [-prove] [-summary] [-q | -format FORMAT] [-threshold LEVEL | -v1 OLDERVERSION -v2 NEWERVERSION | -versions] OLDERREV NEWERREV
+//	modver [-all] [-profile PROFILE] [-constlevel LEVEL] [-enumlevel LEVEL] [-varinitlevel LEVEL] [-interfacerole PKGPATH.TYPE=ROLE ...] [-prove] [-summary] [-q | -format FORMAT] [-threshold LEVEL | -v1 OLDERVERSION -v2 NEWERVERSION] OLDERDIR NEWERDIR
+//	modver explain [RULEID ...]
+//	modver report [-format html|markdown] [-git REPO [-gitcmd GIT_COMMAND]] OLDER NEWER
+//
+// With `explain`,
+// modver prints the catalog entry for each of the given rule IDs
+// (such as MV104):
+// its title, level, rationale, and a before-and-after example.
+// With no rule IDs it lists all the rules.
+// Reports cite the ID of the rule that determined each change's level.
+//
+// With `report`,
+// modver compares OLDER and NEWER
+// (directories, or revisions with -git)
+// and writes a standalone HTML or Markdown report
+// (Markdown by default)
+// of every change it finds.
+// The report begins with a table of the version bump required by each package,
+// followed by a collapsible section per package
+// listing the changes to each object in that package.
+//
+// The words explain and report are subcommands only when they are not also the names of existing directories.
+// Otherwise, as in
+//
+//	modver explain NEWERDIR
+//
+// where explain is a directory,
+// the word is OLDERDIR,
+// as it was before the subcommands existed.
+// To run a subcommand in such a place,
+// run modver from another directory.
+// To refer to such a directory unambiguously,
+// write it as ./explain or ./report.
 //
 // With `-pr URL`,
 // the URL must be that of a github.com pull request
@@ -32,6 +64,75 @@
 // If the command does not exist or is not found in your PATH,
 // modver falls back to using the go-git library.
 //
+// With -all,
+// modver reports every change it finds between OLDER and NEWER
+// (each with its own required version bump),
+// not just the first one.
+// The overall result is the largest of those.
+//
+// With -profile PROFILE,
+// modver applies the given set of rules
+// when deciding the level of some kinds of change.
+// The profile "lenient" is the default.
+// The profile "go1compat" follows the rules of the Go 1 compatibility promise
+// (https://go.dev/doc/go1compat)
+// that the Go team applies to the standard library:
+// function signatures may not change at all,
+// even in ways that leave existing calls valid,
+// and neither may the values of exported constants.
+// The profile "strict" also treats as Major
+// the addition of struct fields
+// (which breaks unkeyed struct literals)
+// and of methods
+// (which can make selectors ambiguous in clients that embed the type).
+//
+// With -constlevel LEVEL,
+// a change in the value of an exported constant
+// requires the given level of version bump
+// (None, Patchlevel, Minor, or Major)
+// instead of the default,
+// which is Patchlevel
+// (or Major with -profile go1compat or strict).
+// With -enumlevel LEVEL,
+// the same is true for constants in an enum-like block
+// (a const declaration group that uses iota),
+// where the default is Major.
+// With -varinitlevel LEVEL,
+// the same is true for a change in the initializer of an exported variable
+// (such as a default setting),
+// where the default is Patchlevel.
+//
+// Adding a method to an exported interface is a Major change
+// if clients may implement the interface,
+// but only Minor if clients have no reason to:
+// when it appears only where the module supplies values to clients,
+// such as in function results,
+// and the module never checks for it with a type assertion.
+// (Under -profile go1compat or strict it is Major either way.)
+// With -interfacerole PKGPATH.TYPE=ROLE,
+// modver treats the named interface type as having the given ROLE,
+// implementable or consumeronly,
+// instead of inferring it.
+// This option may be repeated.
+//
+// With -prove,
+// modver tries to prove each Major change it finds
+// by building a small client program that uses the affected object
+// and type-checking it against the older and newer versions.
+// The change is proven if the program compiles against OLDER but not NEWER.
+// The program, and whether it proves the change,
+// appear in the pretty, json, and junit formats.
+//
+// With -summary,
+// the output is a breakdown of the comparison by package:
+// for each public package,
+// the highest level of change,
+// followed by the exported objects that were added,
+// removed,
+// and changed at each level.
+// The -summary flag implies -all,
+// and cannot be combined with -q or -format.
+//
 // With -v1 and -v2,
 // modver checks whether the change from OLDERVERSION to NEWERVERSION
 // (two version strings)
@@ -51,6 +152,68 @@
 // there is no output,
 // and the exit status is 0, 1, 2, 3, or 4
 // for None, Patchlevel, Minor, Major, and error.
+//
+// With -format FORMAT,
+// output is written in the given format,
+// which is one of text (the default), pretty, json, sarif, or junit.
+// The flags -pretty and -json are shorthand for -format pretty and -format json.
+// The format does not affect the exit status.
+//
+// The pretty format shows the result with (possibly) multiple lines and indentation,
+// including the older and newer declarations of each top-level object that changed.
+//
+// The sarif format is a SARIF 2.1.0 log
+// (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+// with one result per change found.
+// Each result's rule ID is the ID of the catalog rule that determined its level
+// (see "modver explain" below),
+// its level is error, warning, or note for a Major, Minor, or Patchlevel change,
+// and its location is the newer declaration of the affected object
+// (or the older one if the object was removed),
+// relative to the root of the module.
+// The sarif format implies -all.
+//
+// The junit format is a JUnit XML report
+// with one test suite per package
+// and one test case per exported object in either version of the package,
+// plus one for each change to another object that clients can reach,
+// such as an exported method of an unexported type
+// or a type in an internal package.
+// A test case fails if its object changed by more than an allowed level.
+// That level is the highest one for which -v1 and -v2 are adequate,
+// if they are given;
+// otherwise it is the level given with -threshold LEVEL
+// (None, Patchlevel, Minor, or Major),
+// or Minor by default.
+// The -threshold flag may not be combined with -v1, -v2, or -versions.
+// Changes that apply to the whole module
+// (such as to the minimum Go version)
+// are test cases in a suite named "(module)".
+// The junit format implies -all.
+//
+// The json format is a JSON document describing the comparison and its result.
+// The document looks like this:
+//
+//	{
+//	  "version": 1,
+//	  "mode": "git",
+//	  "repo": ".git",
+//	  "older": "HEAD~1",
+//	  "newer": "HEAD",
+//	  "versions": {"older": "v1.2.3", "newer": "v1.3.0", "ok": true},
+//	  "result": {...}
+//	}
+//
+// The "mode" is "dirs", "git", or "pr".
+// The "repo" field appears only in "git" mode,
+// and the "pr" field (the pull-request URL) only in "pr" mode.
+// The "older" and "newer" fields are directories in "dirs" mode
+// and revisions in the other modes.
+// The "versions" field appears only with -v1 and -v2 or -versions.
+// The "result" field is the JSON encoding of a modver.Results value;
+// see its MarshalJSON method.
+// The "version" field is the version of this document format,
+// and will change only if the format changes incompatibly.
 package main
 
 import (
@@ -67,6 +230,27 @@ import (
 const errorStatus = 4
 
 func main() {
+	switch subcommand(os.Args[1:]) {
+	case "explain":
+		if err := doExplain(os.Stdout, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(errorStatus)
+		}
+		return
+
+	case "report":
+		ropts, err := parseReportArgs(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing args: %s\n", err)
+			os.Exit(errorStatus)
+		}
+		if err := doReport(context.Background(), os.Stdout, ropts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in report: %s\n", err)
+			os.Exit(errorStatus)
+		}
+		return
+	}
+
 	opts, err := parseArgs()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing args: %s\n", err)
@@ -78,7 +262,7 @@ func main() {
 		ctx = modver.WithGit(ctx, opts.gitCmd)
 	}
 
-	res, err := doCompare(ctx, opts)
+	res, err := doCompare(ctx, &opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error in comparing: %s\n", err)
 		os.Exit(errorStatus)
@@ -88,62 +272,130 @@ func main() {
 	os.Exit(exitCode)
 }
 
+// subcommand gives the subcommand named by the first of args
+// (the command-line arguments after the command name),
+// or "" if there is none.
+// A word that names an existing directory is not a subcommand,
+// since it may be the OLDERDIR argument.
+func subcommand(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	switch args[0] {
+	case "explain", "report":
+		if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+			return ""
+		}
+		return args[0]
+	}
+	return ""
+}
+
 func doShowResult(out io.Writer, res modver.Result, opts options) int {
+	var okp *bool
+
 	if opts.v1 != "" && opts.v2 != "" {
-		var ok bool
-
-		cmp := semver.Compare(opts.v1, opts.v2)
-		switch res.Code() {
-		case modver.None:
-			ok = cmp <= 0 // v1 <= v2
-
-		case modver.Patchlevel:
-			ok = cmp < 0 // v1 < v2
-
-		case modver.Minor:
-			var (
-				min1 = semver.MajorMinor(opts.v1)
-				min2 = semver.MajorMinor(opts.v2)
-			)
-			ok = semver.Compare(min1, min2) < 0 // min1 < min2
-
-		case modver.Major:
-			var (
-				maj1 = semver.Major(opts.v1)
-				maj2 = semver.Major(opts.v2)
-			)
-			ok = semver.Compare(maj1, maj2) < 0 // maj1 < maj2
+		ok := versionsOK(res.Code(), opts.v1, opts.v2)
+		okp = &ok
+
+		status := 0
+		if !ok {
+			status = 1
 		}
 
-		if ok {
+		if opts.format == "" || opts.format == "pretty" {
 			if !opts.quiet {
-				if opts.versions {
-					fmt.Fprintf(out, "OK using versions %s and %s: %s\n", opts.v1, opts.v2, res)
-				} else {
-					fmt.Fprintf(out, "OK %s\n", res)
+				word := "OK"
+				if !ok {
+					word = "ERR"
+				}
+				switch {
+				case opts.summary && opts.versions:
+					fmt.Fprintf(out, "%s using versions %s and %s: %s\n", word, opts.v1, opts.v2, res.Code())
+					writeSummary(out, opts.pkgSummary)
+				case opts.summary:
+					fmt.Fprintf(out, "%s %s\n", word, res.Code())
+					writeSummary(out, opts.pkgSummary)
+				case opts.versions:
+					fmt.Fprintf(out, "%s using versions %s and %s: %s\n", word, opts.v1, opts.v2, res)
+				default:
+					fmt.Fprintf(out, "%s %s\n", word, res)
 				}
 			}
-			return 0
+			return status
 		}
-		if !opts.quiet {
-			if opts.versions {
-				fmt.Fprintf(out, "ERR using versions %s and %s: %s\n", opts.v1, opts.v2, res)
-			} else {
-				fmt.Fprintf(out, "ERR %s\n", res)
-			}
+
+		if err := writeFormatted(out, res, opts, okp); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s output: %s\n", opts.format, err)
+			return errorStatus
 		}
-		return 1
+		return status
 	}
 
 	if opts.quiet {
 		return int(res.Code())
 	}
 
-	if opts.pretty {
-		modver.Pretty(out, res)
-	} else {
-		fmt.Fprintln(out, res)
+	if err := writeFormatted(out, res, opts, okp); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %s\n", opts.format, err)
+		return errorStatus
 	}
 
 	return 0
 }
+
+// writeFormatted writes res to out in the format selected by opts.
+// If ok is non-nil,
+// it is the outcome of checking the -v1 and -v2 versions against the result.
+func writeFormatted(out io.Writer, res modver.Result, opts options, ok *bool) error {
+	switch opts.format {
+	case "json":
+		return writeJSON(out, res, opts, ok)
+
+	case "sarif":
+		return writeSARIF(out, res, opts)
+
+	case "junit":
+		return writeJUnit(out, res, opts)
+
+	case "pretty":
+		modver.PrettyDecls(out, res)
+
+	default:
+		if opts.summary {
+			writeSummary(out, opts.pkgSummary)
+		} else {
+			fmt.Fprintln(out, res)
+		}
+	}
+
+	return nil
+}
+
+// versionsOK tells whether the change from version v1 to v2 is adequate for a result of code.
+func versionsOK(code modver.ResultCode, v1, v2 string) bool {
+	cmp := semver.Compare(v1, v2)
+	switch code {
+	case modver.None:
+		return cmp <= 0 // v1 <= v2
+
+	case modver.Patchlevel:
+		return cmp < 0 // v1 < v2
+
+	case modver.Minor:
+		var (
+			min1 = semver.MajorMinor(v1)
+			min2 = semver.MajorMinor(v2)
+		)
+		return semver.Compare(min1, min2) < 0 // min1 < min2
+
+	case modver.Major:
+		var (
+			maj1 = semver.Major(v1)
+			maj2 = semver.Major(v2)
+		)
+		return semver.Compare(maj1, maj2) < 0 // maj1 < maj2
+	}
+
+	return false
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/bobg/modver/v2"
@@ -72,3 +73,40 @@ func TestDoShowResult(t *testing.T) {
 		})
 	}
 }
+
+func TestSubcommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("report", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		args []string
+		want string
+	}{{
+		args: nil,
+		want: "",
+	}, {
+		args: []string{"explain", "MV104"},
+		want: "explain",
+	}, {
+		args: []string{"explain", "newer"},
+		want: "explain",
+	}, {
+		// There is a directory named report, so this compares it with newer.
+		args: []string{"report", "newer"},
+		want: "",
+	}, {
+		args: []string{"older", "newer"},
+		want: "",
+	}, {
+		args: []string{"-all", "explain", "newer"},
+		want: "",
+	}}
+
+	for _, tc := range cases {
+		if got := subcommand(tc.args); got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
@@ -8,12 +8,21 @@ import (
 
 	"github.com/bobg/errors"
 	"golang.org/x/mod/semver"
+
+	"github.com/bobg/modver/v2"
 )
 
 type options struct {
-	gitRepo, gitCmd, ghtoken, v1, v2, pr string
-	quiet, pretty, versions              bool
-	args                                 []string
+	gitRepo, gitCmd, ghtoken, v1, v2, pr, format, threshold string
+	constLevel, enumLevel, varInitLevel, profile            string
+	all, prove, quiet, summary, versions                    bool
+	interfaceRoles, args                                    []string
+
+	// These are filled in during the comparison.
+	prBase, prHead     string              // in -pr mode, the base and head revisions of the pull request
+	olderDir, newerDir string              // the directories containing the older and newer versions of the module
+	objects            map[string][]string // with -format junit, the exported objects in each package of either version
+	pkgSummary         modver.Summary      // with -summary, the per-package summary of the comparison
 }
 
 func parseArgs() (options, error) {
@@ -21,15 +30,32 @@ func parseArgs() (options, error) {
 }
 
 func parseArgsHelper(args []string) (opts options, err error) {
-	var fs flag.FlagSet
+	var (
+		fs           flag.FlagSet
+		pretty, json bool
+	)
 
-	fs.BoolVar(&opts.pretty, "pretty", false, "result is shown in a pretty format with (possibly) multiple lines and indentation")
+	fs.BoolVar(&opts.all, "all", false, "report every change found, not just the first")
+	fs.BoolVar(&opts.prove, "prove", false, "try to prove each Major change with a client program that compiles against the older version but not the newer")
+	fs.StringVar(&opts.profile, "profile", "", "rules for deciding the level of some changes: lenient (the default), go1compat, or strict")
+	fs.StringVar(&opts.constLevel, "constlevel", "", "the level (None, Patchlevel, Minor, or Major) of a change in the value of an exported constant; the default is Patchlevel, or Major with -profile go1compat or strict")
+	fs.StringVar(&opts.enumLevel, "enumlevel", "", "the level (None, Patchlevel, Minor, or Major) of a change in the value of an exported constant in an iota block; the default is Major")
+	fs.StringVar(&opts.varInitLevel, "varinitlevel", "", "the level (None, Patchlevel, Minor, or Major) of a change in the initializer of an exported variable; the default is Patchlevel")
+	fs.Func("interfacerole", "PKGPATH.TYPE=ROLE: treat the named interface type as implementable or consumeronly by clients, overriding the role inferred from where it appears in the API (may be repeated)", func(s string) error {
+		opts.interfaceRoles = append(opts.interfaceRoles, s)
+		return nil
+	})
+	fs.StringVar(&opts.format, "format", "", "output format: text (the default), pretty, json, sarif, or junit")
+	fs.BoolVar(&json, "json", false, "same as -format json")
+	fs.BoolVar(&pretty, "pretty", false, "same as -format pretty: result is shown with (possibly) multiple lines and indentation")
+	fs.BoolVar(&opts.summary, "summary", false, "show the highest level of change in each package, and the exported objects added, removed, and changed at each level")
 	fs.BoolVar(&opts.quiet, "q", false, "quiet mode: prints no output, exits with status 0, 1, 2, 3, or 4 to mean None, Patchlevel, Minor, Major, or error")
 	fs.BoolVar(&opts.versions, "versions", false, "with -git, compute values for -v1 and -v2 from the Git repository")
 	fs.StringVar(&opts.ghtoken, "token", os.Getenv("GITHUB_TOKEN"), "GitHub access token")
 	fs.StringVar(&opts.gitCmd, "gitcmd", "git", "use this command for git operations, if found; otherwise use the go-git library")
 	fs.StringVar(&opts.gitRepo, "git", "", "Git repo URL")
 	fs.StringVar(&opts.pr, "pr", "", "URL of GitHub pull request")
+	fs.StringVar(&opts.threshold, "threshold", "", "with -format junit, the highest level of change (None, Patchlevel, Minor, or Major) that does not count as a failure")
 	fs.StringVar(&opts.v1, "v1", "", "version string of older version; with -v2 changes output to OK (exit status 0) for adequate version-number change, ERR (exit status 1) for inadequate")
 	fs.StringVar(&opts.v2, "v2", "", "version string of newer version")
 	if err := fs.Parse(args); err != nil {
@@ -37,6 +63,59 @@ func parseArgsHelper(args []string) (opts options, err error) {
 	}
 	opts.args = fs.Args()
 
+	if pretty || json {
+		if pretty && json {
+			return opts, fmt.Errorf("do not specify both -pretty and -json")
+		}
+		if opts.format != "" {
+			return opts, fmt.Errorf("do not specify -pretty or -json with -format")
+		}
+		if pretty {
+			opts.format = "pretty"
+		} else {
+			opts.format = "json"
+		}
+	}
+	if opts.format == "text" {
+		opts.format = ""
+	}
+
+	switch opts.format {
+	case "", "pretty", "json":
+	case "sarif":
+		// A SARIF log has a result for every change.
+		opts.all = true
+	case "junit":
+		// A JUnit report has a test case for every object, changed or not.
+		opts.all = true
+	default:
+		return opts, fmt.Errorf("unknown output format %s", opts.format)
+	}
+	if opts.summary {
+		if opts.format != "" || opts.quiet {
+			return opts, fmt.Errorf("do not specify -summary with -q or -format")
+		}
+		opts.all = true
+	}
+	if opts.threshold != "" {
+		if opts.format != "junit" {
+			return opts, fmt.Errorf("do not specify -threshold without -format junit")
+		}
+		if opts.v1 != "" || opts.v2 != "" || opts.versions {
+			return opts, fmt.Errorf("do not specify -threshold with -v1, -v2, or -versions")
+		}
+		var code modver.ResultCode
+		if err := code.UnmarshalText([]byte(opts.threshold)); err != nil {
+			return opts, errors.Wrap(err, "parsing -threshold")
+		}
+	}
+	if _, err := opts.compareOptions(); err != nil {
+		return opts, err
+	}
+	if opts.quiet && opts.format != "" {
+		return opts, fmt.Errorf("do not specify -q with -format %s", opts.format)
+	}
+
 	if opts.pr != "" {
 		if opts.gitRepo != "" {
 			return opts, fmt.Errorf("do not specify -git with -pr")
@@ -63,3 +142,52 @@ func parseArgsHelper(args []string) (opts options, err error) {
 
 	return opts, nil
 }
+
+// compareOptions gives the options to pass to modver.Compare and modver.CompareAll.
+func (opts options) compareOptions() ([]modver.Option, error) {
+	var result []modver.Option
+	if opts.profile != "" {
+		var profile modver.Profile
+		if err := profile.UnmarshalText([]byte(opts.profile)); err != nil {
+			return nil, errors.Wrap(err, "parsing -profile")
+		}
+		result = append(result, modver.WithProfile(profile))
+	}
+	if opts.constLevel != "" {
+		var code modver.ResultCode
+		if err := code.UnmarshalText([]byte(opts.constLevel)); err != nil {
+			return nil, errors.Wrap(err, "parsing -constlevel")
+		}
+		result = append(result, modver.WithConstLevel(code))
+	}
+	if opts.enumLevel != "" {
+		var code modver.ResultCode
+		if err := code.UnmarshalText([]byte(opts.enumLevel)); err != nil {
+			return nil, errors.Wrap(err, "parsing -enumlevel")
+		}
+		result = append(result, modver.WithEnumLevel(code))
+	}
+	if opts.varInitLevel != "" {
+		var code modver.ResultCode
+		if err := code.UnmarshalText([]byte(opts.varInitLevel)); err != nil {
+			return nil, errors.Wrap(err, "parsing -varinitlevel")
+		}
+		result = append(result, modver.WithVarInitLevel(code))
+	}
+	for _, s := range opts.interfaceRoles {
+		name, roleStr, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("parsing -interfacerole %s: want PKGPATH.TYPE=ROLE", s)
+		}
+		i := strings.LastIndex(name, ".")
+		if i <= 0 || i == len(name)-1 || strings.Contains(name[i:], "/") {
+			return nil, fmt.Errorf("parsing -interfacerole %s: want a qualified type name like example.com/pkg.Type", s)
+		}
+		var role modver.InterfaceRole
+		if err := role.UnmarshalText([]byte(roleStr)); err != nil {
+			return nil, errors.Wrap(err, "parsing -interfacerole")
+		}
+		result = append(result, modver.WithInterfaceRole(name[:i], name[i+1:], role))
+	}
+	return result, nil
+}
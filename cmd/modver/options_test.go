@@ -46,6 +46,108 @@ func TestParseArgs(t *testing.T) {
 			ghtoken: ghtok,
 			gitCmd:  "git",
 		},
+	}, {
+		args: []string{"-all", "older", "newer"},
+		want: options{
+			all:     true,
+			args:    []string{"older", "newer"},
+			ghtoken: ghtok,
+			gitCmd:  "git",
+		},
+	}, {
+		args:    []string{"-json", "-q"},
+		wantErr: true,
+	}, {
+		args: []string{"-pretty"},
+		want: options{
+			format:  "pretty",
+			ghtoken: ghtok,
+			gitCmd:  "git",
+		},
+	}, {
+		args: []string{"-format", "sarif"},
+		want: options{
+			all:     true,
+			format:  "sarif",
+			ghtoken: ghtok,
+			gitCmd:  "git",
+		},
+	}, {
+		args:    []string{"-format", "sarif", "-json"},
+		wantErr: true,
+	}, {
+		args: []string{"-prove", "older", "newer"},
+		want: options{
+			prove:   true,
+			args:    []string{"older", "newer"},
+			ghtoken: ghtok,
+			gitCmd:  "git",
+		},
+	}, {
+		args: []string{"-format", "junit", "-threshold", "Patchlevel"},
+		want: options{
+			format:    "junit",
+			threshold: "Patchlevel",
+			all:       true,
+			ghtoken:   ghtok,
+			gitCmd:    "git",
+		},
+	}, {
+		args:    []string{"-format", "junit", "-threshold", "Huge"},
+		wantErr: true,
+	}, {
+		args:    []string{"-threshold", "Minor"},
+		wantErr: true,
+	}, {
+		args:    []string{"-format", "junit", "-threshold", "Minor", "-v1", "1.0.0", "-v2", "1.1.0"},
+		wantErr: true,
+	}, {
+		args:    []string{"-format", "junit", "-threshold", "Minor", "-git", "repo", "-versions"},
+		wantErr: true,
+	}, {
+		args: []string{"-constlevel", "Minor", "-enumlevel", "None", "-varinitlevel", "Major"},
+		want: options{
+			constLevel:   "Minor",
+			enumLevel:    "None",
+			varInitLevel: "Major",
+			ghtoken:      ghtok,
+			gitCmd:       "git",
+		},
+	}, {
+		args: []string{"-profile", "strict"},
+		want: options{
+			profile: "strict",
+			ghtoken: ghtok,
+			gitCmd:  "git",
+		},
+	}, {
+		args:    []string{"-profile", "loose"},
+		wantErr: true,
+	}, {
+		args:    []string{"-constlevel", "Huge"},
+		wantErr: true,
+	}, {
+		args:    []string{"-varinitlevel", "Huge"},
+		wantErr: true,
+	}, {
+		args: []string{"-interfacerole", "example.com/foo.Iter=consumeronly", "-interfacerole", "example.com/foo.Logger=implementable"},
+		want: options{
+			interfaceRoles: []string{"example.com/foo.Iter=consumeronly", "example.com/foo.Logger=implementable"},
+			ghtoken:        ghtok,
+			gitCmd:         "git",
+		},
+	}, {
+		args:    []string{"-interfacerole", "example.com/foo.Iter=sealed"},
+		wantErr: true,
+	}, {
+		args:    []string{"-interfacerole", "example.com/foo=consumeronly"},
+		wantErr: true,
+	}, {
+		args:    []string{"-interfacerole", "example.com/foo.Iter"},
+		wantErr: true,
+	}, {
+		args:    []string{"-format", "xml"},
+		wantErr: true,
 	}, {
 		args:    []string{"-v1", "1", "-v2", "bar"},
 		wantErr: true,
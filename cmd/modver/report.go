@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/bobg/errors"
+
+	"github.com/bobg/modver/v2"
+)
+
+type reportOptions struct {
+	format, gitRepo, gitCmd string
+	older, newer            string
+}
+
+func parseReportArgs(args []string) (opts reportOptions, err error) {
+	var fs flag.FlagSet
+
+	fs.StringVar(&opts.format, "format", "markdown", "report format: html or markdown")
+	fs.StringVar(&opts.gitRepo, "git", "", "Git repo URL")
+	fs.StringVar(&opts.gitCmd, "gitcmd", "git", "use this command for git operations, if found; otherwise use the go-git library")
+	if err := fs.Parse(args); err != nil {
+		return opts, errors.Wrap(err, "parsing args")
+	}
+
+	switch opts.format {
+	case "html", "markdown":
+	case "md":
+		opts.format = "markdown"
+	default:
+		return opts, fmt.Errorf("unknown report format %s", opts.format)
+	}
+
+	if fs.NArg() != 2 {
+		return opts, fmt.Errorf("usage: modver report [-format html|markdown] [-git REPO [-gitcmd GIT_COMMAND]] OLDER NEWER")
+	}
+	opts.older, opts.newer = fs.Arg(0), fs.Arg(1)
+
+	return opts, nil
+}
+
+// doReport compares the older and newer versions of a module named in opts
+// and writes a report of every change to out.
+func doReport(ctx context.Context, out io.Writer, opts reportOptions) error {
+	var (
+		res modver.Result
+		err error
+	)
+	if opts.gitRepo != "" {
+		if opts.gitCmd != "" {
+			ctx = modver.WithGit(ctx, opts.gitCmd)
+		}
+		res, err = modver.CompareGitWith(ctx, opts.gitRepo, opts.older, opts.newer, modver.CompareDirsAll)
+	} else {
+		res, err = modver.CompareDirsAll(opts.older, opts.newer)
+	}
+	if err != nil {
+		return errors.Wrap(err, "comparing")
+	}
+
+	return writeReport(out, opts.format, makeReport(opts.older, opts.newer, res))
+}
+
+type (
+	reportData struct {
+		Older, Newer string
+		Code         modver.ResultCode
+		Packages     []*reportPackage
+	}
+
+	reportPackage struct {
+		Path    string
+		Code    modver.ResultCode
+		Objects []*reportObject
+	}
+
+	reportObject struct {
+		Name     string
+		Code     modver.ResultCode
+		Findings []reportFinding
+	}
+
+	reportFinding struct {
+		Code             modver.ResultCode
+		Reasons          []string
+		OldDecl, NewDecl string
+	}
+)
+
+// modulePkg is the name under which the report shows changes that apply to the whole module,
+// such as a change in the minimum Go version.
+const modulePkg = "(module)"
+
+// makeReport groups the changes in res by package and then by object.
+func makeReport(older, newer string, res modver.Result) reportData {
+	data := reportData{Older: older, Newer: newer, Code: res.Code()}
+
+	for _, r := range toResults(res) {
+		changes := r.Changes()
+		if len(changes) == 0 {
+			continue
+		}
+
+		var (
+			top     = changes[0]
+			pkgPath = top.PkgPath
+			objName = top.Object
+		)
+		if pkgPath == "" {
+			pkgPath = modulePkg
+		}
+
+		idx := slices.IndexFunc(data.Packages, func(p *reportPackage) bool { return p.Path == pkgPath })
+		if idx < 0 {
+			idx = len(data.Packages)
+			data.Packages = append(data.Packages, &reportPackage{Path: pkgPath})
+		}
+		pkg := data.Packages[idx]
+		pkg.Code = max(pkg.Code, r.Code())
+
+		idx = slices.IndexFunc(pkg.Objects, func(o *reportObject) bool { return o.Name == objName })
+		if idx < 0 {
+			idx = len(pkg.Objects)
+			pkg.Objects = append(pkg.Objects, &reportObject{Name: objName})
+		}
+		obj := pkg.Objects[idx]
+		obj.Code = max(obj.Code, r.Code())
+
+		finding := reportFinding{Code: r.Code(), OldDecl: top.OldDecl, NewDecl: top.NewDecl}
+		for _, ch := range changes {
+			reason := ch.Reason
+			if ch.Rule != "" {
+				reason += " [" + ch.Rule + "]"
+			}
+			finding.Reasons = append(finding.Reasons, reason)
+		}
+		obj.Findings = append(obj.Findings, finding)
+	}
+
+	slices.SortFunc(data.Packages, func(a, b *reportPackage) int { return strings.Compare(a.Path, b.Path) })
+
+	return data
+}
+
+var (
+	//go:embed report.html.tmpl
+	reportHTMLTplStr string
+
+	//go:embed report.md.tmpl
+	reportMarkdownTplStr string
+
+	reportHTMLTpl     = htmltemplate.Must(htmltemplate.New("").Parse(reportHTMLTplStr))
+	reportMarkdownTpl = texttemplate.Must(texttemplate.New("").Parse(reportMarkdownTplStr))
+)
+
+func writeReport(out io.Writer, format string, data reportData) error {
+	if format == "html" {
+		return reportHTMLTpl.Execute(out, data)
+	}
+	return reportMarkdownTpl.Execute(out, data)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bobg/modver/v2"
+)
+
+func TestParseReportArgs(t *testing.T) {
+	opts, err := parseReportArgs([]string{"older", "newer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.format != "markdown" || opts.older != "older" || opts.newer != "newer" {
+		t.Errorf("got %+v", opts)
+	}
+
+	if _, err := parseReportArgs([]string{"-format", "pdf", "older", "newer"}); err == nil {
+		t.Error("got no error for unknown format")
+	}
+	if _, err := parseReportArgs([]string{"older"}); err == nil {
+		t.Error("got no error for missing argument")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	const (
+		older = "../../testdata/_transitivemajor/a1"
+		newer = "../../testdata/_transitivemajor/a2"
+	)
+
+	res, err := modver.CompareDirsAll(older, newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := makeReport(older, newer, res)
+	if len(data.Packages) != 1 {
+		t.Fatalf("got %d packages, want 1", len(data.Packages))
+	}
+	pkg := data.Packages[0]
+	if pkg.Path != "foo.bar/a" || pkg.Code != modver.Major {
+		t.Errorf("got package %s at level %s, want foo.bar/a at Major", pkg.Path, pkg.Code)
+	}
+	if len(pkg.Objects) != 1 || pkg.Objects[0].Name != "F" {
+		t.Fatalf("got objects %v, want [F]", pkg.Objects)
+	}
+
+	cases := []struct {
+		format string
+		want   []string
+	}{{
+		format: "markdown",
+		want:   []string{"| `foo.bar/a` | Major |", "<summary><code>foo.bar/a</code>: Major</summary>", "### `F`: Major", "```go\nfunc F(val d.D) int\n```"},
+	}, {
+		format: "html",
+		want:   []string{`<tr><td><code>foo.bar/a</code></td><td class="Major">Major</td></tr>`, `<h3><code>F</code>: <span class="Major">Major</span></h3>`, "<pre>func F(val d.D) int</pre>"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := writeReport(buf, tc.format, data); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("report does not contain %q:\n%s", want, buf)
+				}
+			}
+		})
+	}
+}
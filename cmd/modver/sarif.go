@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/bobg/modver/v2"
+)
+
+// Types for a SARIF 2.1.0 log.
+// Only the parts used by modver are included.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string        `json:"id"`
+		Name             string        `json:"name"`
+		ShortDescription sarifMessage  `json:"shortDescription"`
+		FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+	}
+
+	sarifResult struct {
+		RuleID     string          `json:"ruleId"`
+		Level      string          `json:"level"`
+		Message    sarifMessage    `json:"message"`
+		Locations  []sarifLocation `json:"locations,omitempty"`
+		Properties sarifProperties `json:"properties"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+
+	sarifProperties struct {
+		Code modver.ResultCode `json:"modverCode"`
+	}
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// writeSARIF writes res to out as a SARIF log with one result per change found.
+func writeSARIF(out io.Writer, res modver.Result, opts options) error {
+	driver := sarifDriver{
+		Name:           "modver",
+		InformationURI: "https://github.com/bobg/modver",
+		Rules:          []sarifRule{},
+	}
+	results := []sarifResult{}
+
+	for _, r := range toResults(res) {
+		changes := r.Changes()
+		if len(changes) == 0 {
+			continue
+		}
+
+		// The last Change in the chain is the one that determined the result.
+		rule := sarifRuleOf(changes[len(changes)-1])
+		ruleID := rule.ID
+		if !slices.ContainsFunc(driver.Rules, func(rule sarifRule) bool { return rule.ID == ruleID }) {
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		result := sarifResult{
+			RuleID:     ruleID,
+			Level:      sarifLevel(r.Code()),
+			Message:    sarifMessage{Text: r.String()},
+			Properties: sarifProperties{Code: r.Code()},
+		}
+		if loc, ok := sarifLocationOf(changes[0], opts); ok {
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifRuleOf describes the catalog rule of ch,
+// or just its kind if it has no rule.
+func sarifRuleOf(ch modver.Change) sarifRule {
+	if rule, ok := modver.LookupRule(ch.Rule); ok {
+		return sarifRule{
+			ID:               rule.ID,
+			Name:             rule.Kind.String(),
+			ShortDescription: sarifMessage{Text: rule.Title},
+			FullDescription:  &sarifMessage{Text: rule.Rationale},
+		}
+	}
+	name := ch.Kind.String()
+	return sarifRule{
+		ID:               name,
+		Name:             name,
+		ShortDescription: sarifMessage{Text: name},
+	}
+}
+
+func sarifLevel(code modver.ResultCode) string {
+	switch code {
+	case modver.Major:
+		return "error"
+	case modver.Minor:
+		return "warning"
+	case modver.Patchlevel:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// sarifLocationOf gives the location of the newer declaration in ch,
+// or of the older one if there is no newer one.
+// The filename is made relative to the directory that was compared, when possible.
+func sarifLocationOf(ch modver.Change, opts options) (sarifLocation, bool) {
+	pos, dir := ch.NewPos, opts.newerDir
+	if !pos.IsValid() {
+		pos, dir = ch.OldPos, opts.olderDir
+	}
+	if !pos.IsValid() {
+		return sarifLocation{}, false
+	}
+
+	artifact := sarifArtifactLocation{URI: filepath.ToSlash(pos.Filename)}
+	if rel, ok := relativeTo(dir, pos.Filename); ok {
+		artifact = sarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: "%SRCROOT%"}
+	}
+
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+			Region: sarifRegion{
+				StartLine:   pos.Line,
+				StartColumn: pos.Column,
+			},
+		},
+	}, true
+}
+
+// relativeTo gives filename relative to dir,
+// if it is inside dir.
+func relativeTo(dir, filename string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absDir, filename)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bobg/modver/v2"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	const (
+		older = "../../testdata/_transitivemajor/a1"
+		newer = "../../testdata/_transitivemajor/a2"
+	)
+
+	res, err := modver.CompareDirsAll(older, newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := options{format: "sarif", args: []string{older, newer}, olderDir: older, newerDir: newer}
+
+	buf := new(bytes.Buffer)
+	if exitCode := doShowResult(buf, res, opts); exitCode != 0 {
+		t.Fatalf("got exit code %d, want 0", exitCode)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("got version %s, want 2.1.0", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if len(run.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(run.Results))
+	}
+
+	result := run.Results[0]
+	if result.Level != "error" {
+		t.Errorf("got level %s, want error", result.Level)
+	}
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != result.RuleID {
+		t.Errorf("rules %v do not match rule ID %s", run.Tool.Driver.Rules, result.RuleID)
+	}
+	if len(result.Locations) != 1 {
+		t.Fatalf("got %d locations, want 1", len(result.Locations))
+	}
+
+	loc := result.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "a.go" {
+		t.Errorf("got URI %s, want a.go", loc.ArtifactLocation.URI)
+	}
+	if loc.Region.StartLine != 5 {
+		t.Errorf("got line %d, want 5", loc.Region.StartLine)
+	}
+}
+
+func TestSARIFAll(t *testing.T) {
+	// The sarif format implies -all,
+	// so the log has a result for every change,
+	// not just the first.
+	const (
+		gomod = "module foo.bar/s\n\ngo 1.18\n"
+		older = "package s\n\nfunc F() {}\n\nfunc G() {}\n"
+		newer = "package s\n"
+	)
+
+	tmpdir := t.TempDir()
+	for dir, src := range map[string]string{"older": older, "newer": newer} {
+		dir = filepath.Join(tmpdir, dir)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "s.go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts, err := parseArgsHelper([]string{"-format", "sarif", filepath.Join(tmpdir, "older"), filepath.Join(tmpdir, "newer")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := doCompare(context.Background(), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if exitCode := doShowResult(buf, res, opts); exitCode != 0 {
+		t.Fatalf("got exit code %d, want 0", exitCode)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(log.Runs))
+	}
+	if got := len(log.Runs[0].Results); got != 2 {
+		t.Errorf("got %d results, want 2", got)
+	}
+}
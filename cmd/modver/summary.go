@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bobg/modver/v2"
+)
+
+// writeSummary writes a per-package breakdown of a comparison to out.
+func writeSummary(out io.Writer, summary modver.Summary) {
+	pkgPaths := make([]string, 0, len(summary))
+	for pkgPath := range summary {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	slices.Sort(pkgPaths)
+
+	for _, pkgPath := range pkgPaths {
+		ps := summary[pkgPath]
+		name := pkgPath
+		if name == "" {
+			name = modulePkg
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, ps.Code())
+		writeSummaryLine(out, "added", ps.Added)
+		writeSummaryLine(out, "removed", ps.Removed)
+		for code := modver.Major; code > modver.None; code-- {
+			writeSummaryLine(out, "changed ("+code.String()+")", ps.Changed[code])
+		}
+	}
+}
+
+func writeSummaryLine(out io.Writer, label string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "  %s: %s\n", label, strings.Join(ids, ", "))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bobg/modver/v2"
+)
+
+func TestWriteSummary(t *testing.T) {
+	summary := modver.Summary{
+		"example.com/a": {
+			Result:  modver.Results{modver.Major},
+			Added:   []string{"G", "H"},
+			Changed: map[modver.ResultCode][]string{modver.Major: {"F"}, modver.Minor: {"X"}},
+		},
+		"example.com/b": {},
+	}
+
+	buf := new(bytes.Buffer)
+	writeSummary(buf, summary)
+
+	const want = `example.com/a: Major
+  added: G, H
+  changed (Major): F
+  changed (Minor): X
+example.com/b: None
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf, want)
+	}
+}
@@ -16,10 +16,6 @@ import (
 	"github.com/bobg/modver/v2"
 )
 
-func getTags(v1, v2 *string, olderRev, newerRev string) func(older, newer string) (modver.Result, error) {
-	return getTagsHelper(v1, v2, olderRev, newerRev, modver.CompareDirs)
-}
-
 func getTagsHelper(v1, v2 *string, olderRev, newerRev string, compareDirs compareDirsType) func(older, newer string) (modver.Result, error) {
 	return func(older, newer string) (modver.Result, error) {
 		tag, err := getTag(older, olderRev)
@@ -0,0 +1,71 @@
+package modver
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// compareComparability compares obj and newObj,
+// if they are non-interface types,
+// for whether values of them can be compared with == and used as map keys.
+// This can change without any change to the exported parts of a type,
+// e.g. when an unexported field of slice type is added to a struct,
+// or when the same happens to an embedded struct.
+// Losing comparability is a Major change,
+// and gaining it is Minor.
+func (c *comparer) compareComparability(obj, newObj types.Object) Result {
+	if _, ok := obj.(*types.TypeName); !ok {
+		return None
+	}
+	if _, ok := newObj.(*types.TypeName); !ok {
+		return None
+	}
+	typ, newTyp := obj.Type(), newObj.Type()
+	if types.IsInterface(typ) || types.IsInterface(newTyp) {
+		return None
+	}
+	if isGeneric(typ) || isGeneric(newTyp) {
+		// Comparability depends on the type arguments,
+		// and changes in what they may be are found by comparing the type parameters' constraints.
+		return None
+	}
+
+	switch comparable, newComparable := types.Comparable(typ), types.Comparable(newTyp); {
+	case comparable && !newComparable:
+		return c.wrapf(Major, ComparabilityLost, obj, newObj, "%s is no longer comparable%s", obj.Name(), whyNotComparable(newTyp, newObj.Pkg()))
+	case !comparable && newComparable:
+		return c.wrapf(Minor, ComparabilityGained, obj, newObj, "%s is now comparable", obj.Name())
+	}
+	return None
+}
+
+// whyNotComparable explains why typ,
+// a type in pkg,
+// is not comparable,
+// as a phrase to append to a sentence saying so.
+func whyNotComparable(typ types.Type, pkg *types.Package) string {
+	qual := types.RelativeTo(pkg)
+
+	switch u := typ.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			f := u.Field(i)
+			if types.Comparable(f.Type()) {
+				continue
+			}
+			if f.Embedded() {
+				return fmt.Sprintf(", because embedded %s is not", types.TypeString(f.Type(), qual))
+			}
+			return fmt.Sprintf(", because field %s of type %s is not", f.Name(), types.TypeString(f.Type(), qual))
+		}
+
+	case *types.Array:
+		return fmt.Sprintf(", because element type %s is not", types.TypeString(u.Elem(), qual))
+	}
+	return ""
+}
+
+// isGeneric tells whether typ is a generic named type or alias.
+func isGeneric(typ types.Type) bool {
+	return typeParams(typ).Len() > 0
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
@@ -30,7 +32,27 @@ import (
 // Old callers _can_ continue using the new version without being updated,
 // but callers depending on the new features cannot use the old version.
 //
-// A patchlevel bump is needed for most other changes.
+// Marking an object, package, or module as deprecated
+// (with a "Deprecated:" paragraph in its doc comment,
+// or on the module line of go.mod)
+// also needs a minor-version bump.
+// Removing an exported object needs a major-version bump whether or not it was deprecated,
+// but a removal of one that was not deprecated in the older version
+// is reported as a distinct kind of change,
+// RemovedWithoutDeprecation,
+// since a removal should follow a release in which the object was deprecated.
+//
+// A patchlevel bump is needed for most other changes,
+// such as to the body of a function,
+// or moving a type to another package
+// while leaving a forwarding alias (type T = newpkg.T) in the old one.
+// Changes only to comments (other than deprecation notices) or formatting need no bump at all.
+//
+// The public API is made up of the exported declarations of the module's public packages
+// (those that are not internal or main packages),
+// plus the exported methods and fields of any other types reachable from them,
+// such as an unexported struct type returned by an exported function,
+// or a type in an internal package.
 //
 // The result of Compare is the _minimal_ change required.
 // The actual change required may be greater.
@@ -60,30 +82,54 @@ import (
 //
 // in your Config.Mode.
 // See CompareDirs for an example of how to call Compare with the result of packages.Load.
-func Compare(olders, newers []*packages.Package) Result {
+//
+// The behavior of Compare can be adjusted with Options.
+func Compare(olders, newers []*packages.Package, opts ...Option) Result {
 	var (
 		older = makePackageMap(olders)
 		newer = makePackageMap(newers)
 	)
 
-	c := newComparer()
+	c := newComparer(opts...)
+	c.olderFset, c.newerFset = fileSet(olders), fileSet(newers)
 
-	// Look for major-version changes.
-	if res := c.compareMajor(older, newer); res != nil {
-		return res
-	}
+	var res Result = None
+	c.compareAll(older, newer, func(r Result) bool {
+		if r.Code() > res.Code() {
+			res = r
+		}
+		// No need to keep looking once a major-version change is found.
+		return res.Code() < Major
+	})
 
-	// Look for minor-version changes.
-	if res := c.compareMinor(older, newer); res != nil {
-		return res
-	}
+	return res
+}
 
-	// Finally, look for patchlevel-version changes.
-	if res := c.comparePatchlevel(older, newer); res != nil {
-		return res
-	}
+// CompareAll is like Compare,
+// but instead of stopping at the first change it finds,
+// it reports every change between olders and newers,
+// each with its own ResultCode.
+// The Code of the result is the maximum of those.
+//
+// Changes are reported at most once per top-level object,
+// at the highest level that applies to that object.
+// They are sorted by package path and then by object name.
+func CompareAll(olders, newers []*packages.Package, opts ...Option) Results {
+	var (
+		older = makePackageMap(olders)
+		newer = makePackageMap(newers)
+	)
+
+	c := newComparer(opts...)
+	c.olderFset, c.newerFset = fileSet(olders), fileSet(newers)
+
+	var res Results
+	c.compareAll(older, newer, func(r Result) bool {
+		res = append(res, r)
+		return true
+	})
 
-	return None
+	return res
 }
 
 func isPublic(pkgpath string) bool {
@@ -106,149 +152,329 @@ func isPublic(pkgpath string) bool {
 	return true
 }
 
-func (c *comparer) compareMajor(older, newer map[string]*packages.Package) Result {
-	for pkgPath, pkg := range older {
-		if !isPublic(pkgPath) {
-			continue
+// compareAll compares every package and top-level object in older and newer,
+// calling yield for each change it finds.
+// It stops early if yield returns false.
+func (c *comparer) compareAll(older, newer map[string]*packages.Package, yield func(Result) bool) {
+	c.older, c.newer = older, newer
+	c.olderSurface, c.newerSurface = publicSurface(older), publicSurface(newer)
+	c.olderRoles = interfaceRoles(older)
+
+	if res := compareGoVersions(older, newer); res != nil {
+		if !yield(res) {
+			return
+		}
+	}
+	if res := compareModuleDeprecations(older, newer); res != nil {
+		if !yield(res) {
+			return
 		}
+	}
 
-		var (
-			topObjs    = makeTopObjs(pkg)
-			newTopObjs map[string]types.Object
-			newPkg     = newer[pkgPath]
-		)
+	for _, pkgPath := range sortedKeys(older, newer) {
+		if !c.comparePackages(pkgPath, older[pkgPath], newer[pkgPath], yield) {
+			return
+		}
+	}
+}
 
-		for id, obj := range topObjs {
-			if !isExported(id) {
-				continue
+func compareGoVersions(older, newer map[string]*packages.Package) Result {
+	for _, pkgPath := range sortedKeys(newer) {
+		if !isPublic(pkgPath) {
+			continue
+		}
+		oldPkg := older[pkgPath]
+		if oldPkg == nil {
+			continue
+		}
+		if oldMod, newMod := oldPkg.Module, newer[pkgPath].Module; oldMod != nil && newMod != nil {
+			if cmp := semver.Compare("v"+oldMod.GoVersion, "v"+newMod.GoVersion); cmp < 0 {
+				return wrapk(Minor, GoVersionChanged, "minimum Go version changed from %s to %s", oldMod.GoVersion, newMod.GoVersion)
 			}
+		}
+	}
+	return nil
+}
 
-			// Is obj an exported method of an unexported type? (https://github.com/bobg/modver/issues/36)
-			if sig, ok := obj.Type().(*types.Signature); ok {
-				if recv := sig.Recv(); recv != nil {
-					if named, ok := recv.Type().(*types.Named); ok {
-						if !named.Obj().Exported() {
-							continue
-						}
-					}
+// comparePackages compares the older and newer versions of the package at pkgPath,
+// either of which may be nil.
+// It calls yield for each change it finds,
+// and reports false if yield does.
+func (c *comparer) comparePackages(pkgPath string, oldPkg, newPkg *packages.Package, yield func(Result) bool) bool {
+	public := isPublic(pkgPath)
+
+	switch {
+	case newPkg == nil:
+		if public {
+			for id, obj := range makeTopObjs(oldPkg) {
+				if !isExported(id) || isMethodOfUnexportedType(obj) {
+					continue
+				}
+				if pkgDeprecation(oldPkg) == "" && moduleDeprecation(c.older) == "" {
+					return yield(inObject(wrapk(Major, RemovedWithoutDeprecation, "no new version of package %s, and it was not deprecated in the old version", pkgPath), pkgPath, ""))
 				}
+				return yield(inObject(wrapk(Major, PackageRemoved, "no new version of package %s", pkgPath), pkgPath, ""))
 			}
+		}
+		return yield(inObject(wrapk(Patchlevel, PackageRemoved, "no new version of package %s", pkgPath), pkgPath, ""))
 
-			if newPkg == nil {
-				return rwrapf(Major, "no new version of package %s", pkgPath)
-			}
-			if newTopObjs == nil {
-				newTopObjs = makeTopObjs(newPkg)
-			}
-			newObj := newTopObjs[id]
-			if newObj == nil {
-				return rwrapf(Major, "no object %s in new version of package %s", id, pkgPath)
-			}
-			if res := c.compareTypes(obj.Type(), newObj.Type()); res.Code() == Major {
-				return rwrapf(res, "checking %s", id)
+	case oldPkg == nil:
+		if public {
+			for id := range makeTopObjs(newPkg) {
+				if isExported(id) {
+					return yield(inObject(wrapk(Minor, PackageAdded, "no old version of package %s", pkgPath), pkgPath, ""))
+				}
 			}
 		}
+		return true
 	}
 
-	return nil
-}
-
-func (c *comparer) compareMinor(older, newer map[string]*packages.Package) Result {
-	for pkgPath, pkg := range newer {
-		if !isPublic(pkgPath) {
-			continue
+	if public {
+		if res := comparePackageDeprecations(pkgPath, oldPkg, newPkg); res != nil {
+			if !yield(res) {
+				return false
+			}
 		}
+	}
 
-		oldPkg := older[pkgPath]
-		if oldPkg != nil {
-			if oldMod, newMod := oldPkg.Module, pkg.Module; oldMod != nil && newMod != nil {
-				if cmp := semver.Compare("v"+oldMod.GoVersion, "v"+newMod.GoVersion); cmp < 0 {
-					return rwrapf(Minor, "minimum Go version changed from %s to %s", oldMod.GoVersion, newMod.GoVersion)
-				}
-			}
+	if res := compareBlanks(pkgPath, oldPkg, newPkg); res.Code() != None {
+		if !yield(res) {
+			return false
 		}
+	}
+
+	var (
+		topObjs    = makeTopObjs(oldPkg)
+		newTopObjs = makeTopObjs(newPkg)
 
+		// Methods whose receivers changed between pointer and value
+		// are compared under their old names.
+		recvChanges = matchRecvChanges(topObjs, newTopObjs)
+		newIDs      = make(map[string]bool)
+
+		// Methods of types that moved to other packages behind forwarding aliases
+		// are compared with the same methods of the moved types.
+		movedMethods = c.matchMovedMethods(pkgPath, topObjs, newTopObjs)
+	)
+	for _, newID := range recvChanges {
+		newIDs[newID] = true
+	}
+	for _, id := range sortedKeys(topObjs, newTopObjs) {
+		if newIDs[id] {
+			continue
+		}
 		var (
-			topObjs    = makeTopObjs(pkg)
-			oldTopObjs map[string]types.Object
+			obj, newObj = topObjs[id], newTopObjs[id]
+			newDeclPkg  = newPkg
 		)
-
-		for id, obj := range topObjs {
-			if !isExported(id) {
-				continue
-			}
-			if oldPkg == nil {
-				return rwrapf(Minor, "no old version of package %s", pkgPath)
+		if newID, ok := recvChanges[id]; ok {
+			newObj = newTopObjs[newID]
+		}
+		if m, ok := movedMethods[id]; ok {
+			newObj = m
+			if p := c.newer[m.Pkg().Path()]; p != nil {
+				newDeclPkg = p
 			}
-			if oldTopObjs == nil {
-				oldTopObjs = makeTopObjs(oldPkg)
+		}
+		if res := c.compareObjects(pkgPath, id, obj, newObj); res.Code() != None {
+			res = withDecls(res, declSnippet(oldPkg, obj), declSnippet(newDeclPkg, newObj))
+			if !yield(res) {
+				return false
 			}
-			oldObj := oldTopObjs[id]
-			if oldObj == nil {
-				return rwrapf(Minor, "no object %s in old version of package %s", id, pkgPath)
+		}
+	}
+
+	return true
+}
+
+// compareObjects compares the older and newer versions of the top-level object id in the package at pkgPath.
+// Either obj or newObj may be nil,
+// meaning the object is absent from that version.
+func (c *comparer) compareObjects(pkgPath, id string, obj, newObj types.Object) Result {
+	switch {
+	case obj == nil:
+		if isPublicObject(c.newerSurface, pkgPath, id, newObj) {
+			if fn, ok := newObj.(*types.Func); ok && recvNamed(fn) != nil {
+				return inObject(c.wrapf(c.level(MethodAdded, Minor), MethodAdded, nil, newObj, "no method %s in old version of package %s", id, pkgPath), pkgPath, id)
 			}
-			if res := c.compareTypes(oldObj.Type(), obj.Type()); res.Code() >= Minor {
-				return rwrapf(res.sub(Minor), "checking %s", id)
+			return inObject(c.wrapf(Minor, ObjectAdded, nil, newObj, "no object %s in old version of package %s", id, pkgPath), pkgPath, id)
+		}
+		return None
+
+	case newObj == nil:
+		public := isPublicObject(c.olderSurface, pkgPath, id, obj)
+		if public && c.isPromotedInNewer(pkgPath, obj) {
+			// The method moved to an embedded type.
+			// Its receiver type's method set is compared in compareMethodSets.
+			return None
+		}
+		if public {
+			if !c.wasDeprecated(pkgPath, obj) {
+				return inObject(c.wrapf(Major, RemovedWithoutDeprecation, obj, nil, "no object %s in new version of package %s, and it was not deprecated in the old version", id, pkgPath), pkgPath, id)
 			}
+			return inObject(c.wrapf(Major, ObjectRemoved, obj, nil, "no object %s in new version of package %s", id, pkgPath), pkgPath, id)
 		}
+		return inObject(c.wrapf(Patchlevel, ObjectRemoved, obj, nil, "no object %s in new version of package %s", id, pkgPath), pkgPath, id)
 	}
 
-	return nil
+	public := isPublicObject(c.olderSurface, pkgPath, id, obj)
+
+	res, ok := c.compareObjectKinds(obj, newObj)
+	if !ok {
+		res = c.compareAliasTypeParams(obj, newObj)
+		if r := c.compareTypes(obj.Type(), newObj.Type()); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareRecvs(obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareMethodSets(obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareComparability(obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareMoved(pkgPath, obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+	}
+	switch {
+	case res.Code() == None:
+		// Nothing to adjust.
+	case public && res.Code() == Major:
+		// Leave res as is.
+	case public && res.Code() >= Minor:
+		res = res.sub(Minor)
+	default:
+		res = res.sub(Patchlevel)
+	}
+	if public {
+		if r := c.compareConstValues(pkgPath, obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareVarInits(pkgPath, obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+		if r := c.compareDeprecations(obj, newObj); r.Code() > res.Code() {
+			res = r
+		}
+	}
+	if res.Code() == None {
+		res = c.compareImpls(pkgPath, id, obj, newObj)
+	}
+	if res.Code() == None {
+		return None
+	}
+	return inObject(c.wrapf(res, ObjectChanged, obj, newObj, "checking %s", id), pkgPath, id)
 }
 
-func (c *comparer) comparePatchlevel(older, newer map[string]*packages.Package) Result {
-	for pkgPath, pkg := range older {
-		var (
-			topObjs = makeTopObjs(pkg)
-			newPkg  = newer[pkgPath]
-		)
-		if newPkg == nil {
-			return rwrapf(Patchlevel, "no new version of package %s", pkgPath)
-		}
-		newTopObjs := makeTopObjs(newPkg)
-		for id, obj := range topObjs {
-			newObj := newTopObjs[id]
-			if newObj == nil {
-				return rwrapf(Patchlevel, "no object %s in new version of package %s", id, pkgPath)
-			}
-			if res := c.compareTypes(obj.Type(), newObj.Type()); res.Code() != None {
-				return rwrapf(res.sub(Patchlevel), "checking %s", id)
+// ExportedObjects returns the names of the exported top-level objects in each public package in pkgs,
+// keyed by package path.
+// Method names are qualified with their receiver types,
+// as in Change.Object.
+// Each list of names is sorted.
+func ExportedObjects(pkgs []*packages.Package) map[string][]string {
+	result := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if !isPublic(pkg.PkgPath) {
+			continue
+		}
+		topObjs := makeTopObjs(pkg)
+		for _, id := range sortedKeys(topObjs) {
+			if isExported(id) && !isMethodOfUnexportedType(topObjs[id]) {
+				result[pkg.PkgPath] = append(result[pkg.PkgPath], id)
 			}
 		}
 	}
+	return result
+}
 
-	return nil
+// Is obj an exported method of an unexported type? (https://github.com/bobg/modver/issues/36)
+func isMethodOfUnexportedType(obj types.Object) bool {
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		return false
+	}
+	recv := sig.Recv()
+	if recv == nil {
+		return false
+	}
+	named, ok := recv.Type().(*types.Named)
+	return ok && !named.Obj().Exported()
+}
+
+// fileSet returns the token.FileSet shared by pkgs,
+// or nil if pkgs is empty.
+func fileSet(pkgs []*packages.Package) *token.FileSet {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return pkgs[0].Fset
+}
+
+// sortedKeys returns the union of the keys of ms, sorted.
+func sortedKeys[T any](ms ...map[string]T) []string {
+	var keys []string
+	for _, m := range ms {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
 }
 
 // CompareDirs loads Go modules from the directories at older and newer
 // and calls Compare on the results.
 func CompareDirs(older, newer string) (Result, error) {
+	olders, newers, err := LoadDirs(older, newer)
+	if err != nil {
+		return None, err
+	}
+	return Compare(olders, newers), nil
+}
+
+// CompareDirsAll loads Go modules from the directories at older and newer
+// and calls CompareAll on the results.
+// The Result it returns on success is of type Results.
+func CompareDirsAll(older, newer string) (Result, error) {
+	olders, newers, err := LoadDirs(older, newer)
+	if err != nil {
+		return None, err
+	}
+	return CompareAll(olders, newers), nil
+}
+
+// LoadDirs loads the Go packages of the modules in the directories at older and newer,
+// in the form needed by Compare and CompareAll.
+// It is an error if any package has load errors.
+func LoadDirs(older, newer string) (olders, newers []*packages.Package, err error) {
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedModule,
 		Dir:  older,
 	}
-	olders, err := packages.Load(cfg, "./...")
+	olders, err = packages.Load(cfg, "./...")
 	if err != nil {
-		return None, fmt.Errorf("loading %s/...: %w", older, err)
+		return nil, nil, fmt.Errorf("loading %s/...: %w", older, err)
 	}
 	for _, p := range olders {
 		if len(p.Errors) > 0 {
-			return None, errpkg{pkg: p}
+			return nil, nil, errpkg{pkg: p}
 		}
 	}
 
 	cfg.Dir = newer
-	newers, err := packages.Load(cfg, "./...")
+	newers, err = packages.Load(cfg, "./...")
 	if err != nil {
-		return None, fmt.Errorf("loading %s/...: %w", newer, err)
+		return nil, nil, fmt.Errorf("loading %s/...: %w", newer, err)
 	}
 	for _, p := range newers {
 		if len(p.Errors) > 0 {
-			return None, errpkg{pkg: p}
+			return nil, nil, errpkg{pkg: p}
 		}
 	}
 
-	return Compare(olders, newers), nil
+	return olders, newers, nil
 }
 
 type errpkg struct {
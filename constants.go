@@ -0,0 +1,237 @@
+package modver
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// compareConstValues compares the values of obj and newObj,
+// top-level objects in the package at pkgPath,
+// if they are both constants.
+func (c *comparer) compareConstValues(pkgPath string, obj, newObj types.Object) Result {
+	oldConst, ok := obj.(*types.Const)
+	if !ok {
+		return None
+	}
+	newConst, ok := newObj.(*types.Const)
+	if !ok {
+		return None
+	}
+
+	oldVal, newVal := oldConst.Val(), newConst.Val()
+	if oldVal.Kind() == newVal.Kind() && constant.Compare(oldVal, token.EQL, newVal) {
+		return None
+	}
+
+	if r := c.compareConstRange(pkgPath, oldConst, newConst); r.Code() == Major {
+		return r
+	}
+
+	if c.isEnumConst(c.older[pkgPath], oldConst) || c.isEnumConst(c.newer[pkgPath], newConst) {
+		return c.wrapf(c.level(EnumValueChanged, Major), EnumValueChanged, obj, newObj, "value of constant %s in an iota block changed from %s to %s", obj.Name(), oldVal, newVal)
+	}
+	return c.wrapf(c.level(ConstValueChanged, Patchlevel), ConstValueChanged, obj, newObj, "value of constant %s changed from %s to %s", obj.Name(), oldVal, newVal)
+}
+
+// compareConstRange compares the values of obj and newObj,
+// untyped constants in the package at pkgPath,
+// for whether they are representable by the types obj is used with:
+// its default type,
+// and the types to which the older package converts it implicitly
+// (e.g. by passing it to a function parameter of type int8).
+// Clients may use the constant in the same ways,
+// so a new value that no longer fits one of those types is a Major change.
+func (c *comparer) compareConstRange(pkgPath string, obj, newObj *types.Const) Result {
+	typ, ok := obj.Type().(*types.Basic)
+	if !ok || !isUntyped(typ) {
+		return None
+	}
+	newTyp, ok := newObj.Type().(*types.Basic)
+	if !ok || !isUntyped(newTyp) {
+		return None
+	}
+
+	oldVal, newVal := obj.Val(), newObj.Val()
+	for _, t := range constUseTypes(c.older[pkgPath], obj) {
+		if representable(typ, oldVal, t) && !representable(newTyp, newVal, t) {
+			return c.wrapf(Major, ConstOutOfRange, obj, newObj, "value of constant %s changed from %s to %s, which is not representable by type %s", obj.Name(), oldVal, newVal, t)
+		}
+	}
+	return None
+}
+
+// constUseTypes gives the types that obj,
+// an untyped constant in pkg,
+// is used with:
+// its default type,
+// followed by the types of its uses in pkg
+// after implicit conversion,
+// in order by name.
+func constUseTypes(pkg *packages.Package, obj *types.Const) []types.Type {
+	result := []types.Type{types.Default(obj.Type())}
+	if pkg == nil {
+		return result
+	}
+
+	uses := make(map[string]types.Type)
+	for id, used := range pkg.TypesInfo.Uses {
+		if used != obj {
+			continue
+		}
+		if tv, ok := pkg.TypesInfo.Types[id]; ok && tv.Type != nil && !isUntyped(tv.Type) {
+			uses[tv.Type.String()] = tv.Type
+		}
+	}
+	for _, name := range sortedKeys(uses) {
+		if t := uses[name]; !types.Identical(t, result[0]) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// representableValue tells whether val,
+// the value of an untyped constant,
+// is representable by a value of the basic type t.
+// The sizes of int, uint, and uintptr are taken to be 64 bits.
+func representableValue(val constant.Value, t *types.Basic) bool {
+	switch info := t.Info(); {
+	case info&types.IsInteger != 0:
+		x := constant.ToInt(val)
+		if x.Kind() != constant.Int {
+			return false
+		}
+		if info&types.IsUntyped != 0 {
+			return true
+		}
+		if info&types.IsUnsigned != 0 {
+			u, ok := constant.Uint64Val(x)
+			if !ok {
+				return false
+			}
+			switch t.Kind() {
+			case types.Uint8:
+				return u <= math.MaxUint8
+			case types.Uint16:
+				return u <= math.MaxUint16
+			case types.Uint32:
+				return u <= math.MaxUint32
+			}
+			return true
+		}
+		i, ok := constant.Int64Val(x)
+		if !ok {
+			return false
+		}
+		switch t.Kind() {
+		case types.Int8:
+			return math.MinInt8 <= i && i <= math.MaxInt8
+		case types.Int16:
+			return math.MinInt16 <= i && i <= math.MaxInt16
+		case types.Int32:
+			return math.MinInt32 <= i && i <= math.MaxInt32
+		}
+		return true
+
+	case info&types.IsFloat != 0:
+		x := constant.ToFloat(val)
+		if x.Kind() != constant.Float && x.Kind() != constant.Int {
+			return false
+		}
+		return representableFloat(x, t.Kind())
+
+	case info&types.IsComplex != 0:
+		x := constant.ToComplex(val)
+		if x.Kind() != constant.Complex {
+			return false
+		}
+		var part types.BasicKind
+		switch t.Kind() {
+		case types.Complex64:
+			part = types.Float32
+		case types.Complex128:
+			part = types.Float64
+		default:
+			return true
+		}
+		return representableFloat(constant.Real(x), part) && representableFloat(constant.Imag(x), part)
+
+	case info&types.IsString != 0:
+		return val.Kind() == constant.String
+
+	case info&types.IsBoolean != 0:
+		return val.Kind() == constant.Bool
+	}
+
+	return false
+}
+
+// representableFloat tells whether x,
+// a numeric constant,
+// is in the range of the floating-point type of the given kind
+// (after rounding).
+func representableFloat(x constant.Value, kind types.BasicKind) bool {
+	switch kind {
+	case types.Float32:
+		f, _ := constant.Float32Val(x)
+		return !math.IsInf(float64(f), 0)
+	case types.Float64:
+		f, _ := constant.Float64Val(x)
+		return !math.IsInf(f, 0)
+	}
+	return true
+}
+
+// isEnumConst tells whether obj, a constant in pkg,
+// is in an enum-like block:
+// a const declaration group that uses iota.
+func (c *comparer) isEnumConst(pkg *packages.Package, obj *types.Const) bool {
+	if pkg == nil {
+		return false
+	}
+	enums, ok := c.enumConsts[pkg]
+	if !ok {
+		enums = findEnumConsts(pkg)
+		c.enumConsts[pkg] = enums
+	}
+	return enums[obj]
+}
+
+func findEnumConsts(pkg *packages.Package) map[types.Object]bool {
+	iota := types.Universe.Lookup("iota")
+
+	result := make(map[types.Object]bool)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+
+			var usesIota bool
+			ast.Inspect(gd, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && pkg.TypesInfo.Uses[id] == iota {
+					usesIota = true
+				}
+				return !usesIota
+			})
+			if !usesIota {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					if obj := pkg.TypesInfo.Defs[name]; obj != nil {
+						result[obj] = true
+					}
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package modver
+
+import (
+	"go/constant"
+	"go/types"
+	"math"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestConstLevels(t *testing.T) {
+	cases := []struct {
+		dir, name string
+		opts      []Option
+		want      ResultCode
+	}{{
+		dir: "patchlevel", name: "chconstant",
+		opts: []Option{WithConstLevel(Minor)},
+		want: Minor,
+	}, {
+		dir: "patchlevel", name: "chconstant",
+		opts: []Option{WithConstLevel(None)},
+		want: None,
+	}, {
+		dir: "major", name: "chenum",
+		opts: []Option{WithEnumLevel(Patchlevel)},
+		want: Minor, // from adding Green
+	}}
+
+	for _, tc := range cases {
+		compareCase(t, tc.dir, tc.name, func(t *testing.T, olders, newers []*packages.Package) {
+			if got := CompareAll(olders, newers, tc.opts...).Code(); got != tc.want {
+				t.Errorf("with %d option(s): got %s, want %s", len(tc.opts), got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRepresentableValue(t *testing.T) {
+	cases := []struct {
+		val  constant.Value
+		kind types.BasicKind
+		want bool
+	}{
+		{val: constant.MakeInt64(127), kind: types.Int8, want: true},
+		{val: constant.MakeInt64(128), kind: types.Int8, want: false},
+		{val: constant.MakeInt64(-128), kind: types.Int8, want: true},
+		{val: constant.MakeInt64(300), kind: types.Int64, want: true},
+		{val: constant.MakeInt64(255), kind: types.Uint8, want: true},
+		{val: constant.MakeInt64(-1), kind: types.Uint, want: false},
+		{val: constant.MakeUint64(math.MaxUint64), kind: types.Uint64, want: true},
+		{val: constant.MakeUint64(math.MaxUint64), kind: types.Int, want: false},
+		{val: constant.MakeInt64('a'), kind: types.Uint8, want: true},
+		{val: constant.MakeFloat64(1.0), kind: types.Int, want: true},
+		{val: constant.MakeFloat64(1.5), kind: types.Int, want: false},
+		{val: constant.MakeFloat64(1e300), kind: types.Float32, want: false},
+		{val: constant.MakeFloat64(1e300), kind: types.Float64, want: true},
+		{val: constant.MakeImag(constant.MakeFloat64(1e300)), kind: types.Complex64, want: false},
+		{val: constant.MakeInt64(1), kind: types.Complex128, want: true},
+		{val: constant.MakeString("x"), kind: types.String, want: true},
+		{val: constant.MakeString("x"), kind: types.Int, want: false},
+		{val: constant.MakeBool(true), kind: types.Bool, want: true},
+	}
+
+	for _, tc := range cases {
+		if got := representableValue(tc.val, types.Typ[tc.kind]); got != tc.want {
+			t.Errorf("%s in %s: got %v, want %v", tc.val, types.Typ[tc.kind], got, tc.want)
+		}
+	}
+}
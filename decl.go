@@ -0,0 +1,109 @@
+package modver
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// declSnippet returns the source text of the declaration of the top-level object obj in pkg,
+// as formatted by go/printer,
+// without its doc comment or (for a function) its body.
+// It returns "" if the declaration cannot be found.
+func declSnippet(pkg *packages.Package, obj types.Object) string {
+	if pkg == nil || obj == nil {
+		return ""
+	}
+	node := findDecl(pkg, obj)
+	if node == nil {
+		return ""
+	}
+
+	buf := new(bytes.Buffer)
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(buf, pkg.Fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// findDecl finds the declaration of the top-level object obj in pkg.
+// The result is a copy, stripped of doc comments and function bodies,
+// and with only the relevant spec of a grouped declaration.
+func findDecl(pkg *packages.Package, obj types.Object) ast.Decl {
+	switch node := findDeclNode(pkg, obj).(type) {
+	case *ast.FuncDecl:
+		fd := *node
+		fd.Doc, fd.Body = nil, nil
+		return &fd
+
+	case *ast.TypeSpec:
+		ts := *node
+		ts.Doc, ts.Comment = nil, nil
+		return &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{&ts}}
+
+	case *ast.ValueSpec:
+		vs := *node
+		vs.Doc, vs.Comment = nil, nil
+		tok := token.VAR
+		if _, ok := obj.(*types.Const); ok {
+			tok = token.CONST
+		}
+		return &ast.GenDecl{Tok: tok, Specs: []ast.Spec{&vs}}
+	}
+	return nil
+}
+
+// findDeclNode finds the syntax node declaring the top-level object obj in pkg:
+// an *ast.FuncDecl, *ast.TypeSpec, or *ast.ValueSpec.
+// It returns nil if there is none.
+func findDeclNode(pkg *packages.Package, obj types.Object) ast.Node {
+	pos := obj.Pos()
+	for _, file := range pkg.Syntax {
+		if pos < file.Pos() || pos >= file.End() {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if pos < decl.Pos() || pos >= decl.End() {
+				continue
+			}
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Pos() == pos {
+					return decl
+				}
+
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.Pos() == pos {
+							return spec
+						}
+
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.Pos() == pos {
+								return spec
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// withDecls records in r (if it is a wrapper) the source of the older and newer declarations of the top-level object it concerns.
+func withDecls(r Result, oldDecl, newDecl string) Result {
+	if w, ok := r.(wrapped); ok {
+		w.change.OldDecl, w.change.NewDecl = oldDecl, newDecl
+		return w
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package modver
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecls(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "multiple", func(olderTestDir, newerTestDir string) {
+		res, err := CompareDirsAll(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantDecls := map[string][2]string{
+			"F":     {"func F(int)", "func F(int, int)"},
+			"G":     {"", "func G()"},
+			"X":     {"type X struct {\n\tA int\n}", "type X struct {\n\tA int\n\tB string\n}"},
+			"unexp": {"func unexp()", ""},
+		}
+		for _, r := range res.(Results) {
+			ch := r.Changes()[0]
+			want, ok := wantDecls[ch.Object]
+			if !ok {
+				t.Errorf("unexpected change in %s", ch.Object)
+				continue
+			}
+			if ch.OldDecl != want[0] {
+				t.Errorf("%s: got older declaration %q, want %q", ch.Object, ch.OldDecl, want[0])
+			}
+			if ch.NewDecl != want[1] {
+				t.Errorf("%s: got newer declaration %q, want %q", ch.Object, ch.NewDecl, want[1])
+			}
+		}
+
+		buf := new(bytes.Buffer)
+		PrettyDecls(buf, res)
+		if !strings.Contains(buf.String(), "  older:\n      func F(int)\n  newer:\n      func F(int, int)\n") {
+			t.Errorf("declarations missing from pretty output:\n%s", buf)
+		}
+
+		buf.Reset()
+		Pretty(buf, res)
+		if strings.Contains(buf.String(), "older:") {
+			t.Errorf("unexpected declarations in pretty output:\n%s", buf)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
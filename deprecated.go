@@ -0,0 +1,175 @@
+package modver
+
+import (
+	"go/ast"
+	"go/types"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// compareDeprecations compares obj and newObj,
+// the older and newer versions of a top-level object,
+// for whether newObj is newly marked as deprecated
+// with a "Deprecated:" paragraph in its doc comment.
+// That is a Minor change:
+// clients should stop using the object,
+// but it still works.
+func (c *comparer) compareDeprecations(obj, newObj types.Object) Result {
+	if objDeprecation(c.older, obj) != "" {
+		return None
+	}
+	if msg := objDeprecation(c.newer, newObj); msg != "" {
+		return c.wrapf(Minor, DeprecationAdded, obj, newObj, "%s is newly deprecated: %s", obj.Name(), msg)
+	}
+	return None
+}
+
+// comparePackageDeprecations compares the older and newer versions of the package at pkgPath
+// for whether the newer one is newly marked as deprecated
+// in its package doc comment.
+func comparePackageDeprecations(pkgPath string, oldPkg, newPkg *packages.Package) Result {
+	if pkgDeprecation(oldPkg) != "" {
+		return nil
+	}
+	if msg := pkgDeprecation(newPkg); msg != "" {
+		return inObject(wrapk(Minor, DeprecationAdded, "package %s is newly deprecated: %s", pkgPath, msg), pkgPath, "")
+	}
+	return nil
+}
+
+// compareModuleDeprecations compares the older and newer versions of the module
+// for whether the newer one is newly marked as deprecated
+// in a comment on the module line of its go.mod file.
+func compareModuleDeprecations(older, newer map[string]*packages.Package) Result {
+	if moduleDeprecation(older) != "" {
+		return nil
+	}
+	if msg := moduleDeprecation(newer); msg != "" {
+		return wrapk(Minor, DeprecationAdded, "module is newly deprecated: %s", msg)
+	}
+	return nil
+}
+
+// wasDeprecated tells whether obj,
+// a top-level object in the older version of the package at pkgPath,
+// was marked as deprecated:
+// in its own doc comment,
+// in that of its receiver type if it is a method,
+// in the package doc comment,
+// or on the module line of go.mod.
+func (c *comparer) wasDeprecated(pkgPath string, obj types.Object) bool {
+	if objDeprecation(c.older, obj) != "" {
+		return true
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if named := recvNamed(fn); named != nil && objDeprecation(c.older, named.Obj()) != "" {
+			return true
+		}
+	}
+	return pkgDeprecation(c.older[pkgPath]) != "" || moduleDeprecation(c.older) != ""
+}
+
+// objDeprecation gives the text of the "Deprecated:" paragraph in the doc comment of obj,
+// a top-level object in one of pkgs
+// (keyed by package path),
+// or "" if there is none.
+func objDeprecation(pkgs map[string]*packages.Package, obj types.Object) string {
+	if obj == nil || obj.Pkg() == nil {
+		return ""
+	}
+	pkg := pkgs[obj.Pkg().Path()]
+	if pkg == nil {
+		return ""
+	}
+	return deprecation(findDoc(pkg, obj))
+}
+
+// pkgDeprecation gives the text of the "Deprecated:" paragraph in the package doc comment of pkg,
+// or "" if there is none.
+func pkgDeprecation(pkg *packages.Package) string {
+	if pkg == nil {
+		return ""
+	}
+	for _, file := range pkg.Syntax {
+		if msg := deprecation(file.Doc); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// moduleDeprecation gives the deprecation message in the go.mod file of the module made up of pkgs,
+// or "" if there is none.
+func moduleDeprecation(pkgs map[string]*packages.Package) string {
+	for _, pkgPath := range sortedKeys(pkgs) {
+		mod := pkgs[pkgPath].Module
+		if mod == nil || mod.GoMod == "" {
+			continue
+		}
+		data, err := os.ReadFile(mod.GoMod)
+		if err != nil {
+			return ""
+		}
+		f, err := modfile.ParseLax(mod.GoMod, data, nil)
+		if err != nil || f.Module == nil {
+			return ""
+		}
+		return f.Module.Deprecated
+	}
+	return ""
+}
+
+// findDoc finds the doc comment of the top-level object obj in pkg.
+// For a type, constant, or variable without a doc comment of its own,
+// that is the doc comment of the enclosing declaration,
+// which may be a group.
+func findDoc(pkg *packages.Package, obj types.Object) *ast.CommentGroup {
+	switch node := findDeclNode(pkg, obj).(type) {
+	case *ast.FuncDecl:
+		return node.Doc
+
+	case *ast.TypeSpec:
+		if node.Doc != nil {
+			return node.Doc
+		}
+
+	case *ast.ValueSpec:
+		if node.Doc != nil {
+			return node.Doc
+		}
+
+	default:
+		return nil
+	}
+
+	pos := obj.Pos()
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Pos() <= pos && pos < gd.End() {
+				return gd.Doc
+			}
+		}
+	}
+	return nil
+}
+
+// deprecation gives the text of the "Deprecated:" paragraph in doc,
+// or "" if there is none.
+// See https://go.dev/wiki/Deprecated.
+func deprecation(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, para := range strings.Split(doc.Text(), "\n\n") {
+		if rest, ok := strings.CutPrefix(para, "Deprecated:"); ok {
+			if msg := strings.Join(strings.Fields(rest), " "); msg != "" {
+				return msg
+			}
+			return "(no explanation given)"
+		}
+	}
+	return ""
+}
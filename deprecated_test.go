@@ -0,0 +1,39 @@
+package modver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestDeprecation(t *testing.T) {
+	cases := []struct {
+		src, want string
+	}{{
+		src:  "// F does things.\nfunc F() {}",
+		want: "",
+	}, {
+		src:  "// F does things.\n//\n// Deprecated: use G instead.\nfunc F() {}",
+		want: "use G instead.",
+	}, {
+		src:  "// F does things.\n//\n// Deprecated: use G,\n// which is faster.\n//\n// More about F.\nfunc F() {}",
+		want: "use G, which is faster.",
+	}, {
+		src:  "// Deprecated:\nfunc F() {}",
+		want: "(no explanation given)",
+	}, {
+		src:  "// F is not Deprecated: it is fine.\nfunc F() {}",
+		want: "",
+	}}
+
+	for _, tc := range cases {
+		f, err := parser.ParseFile(token.NewFileSet(), "x.go", "package x\n\n"+tc.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := deprecation(f.Decls[0].(*ast.FuncDecl).Doc); got != tc.want {
+			t.Errorf("for %q got %q, want %q", tc.src, got, tc.want)
+		}
+	}
+}
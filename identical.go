@@ -32,6 +32,17 @@ func (c *comparer) identical(a, b types.Type) (res bool) {
 	c.stack = append(c.stack, tp)
 	defer func() { c.stack = c.stack[:len(c.stack)-1] }()
 
+	// Type parameters are identical if they are in the same position
+	// in their type parameter lists.
+	// (Their constraints are compared along with the lists.)
+	if ta, ok := a.(*types.TypeParam); ok {
+		tb, ok := b.(*types.TypeParam)
+		return ok && ta.Index() == tb.Index()
+	}
+	if _, ok := b.(*types.TypeParam); ok {
+		return false
+	}
+
 	if na, ok := a.(*types.Named); ok {
 		if nb, ok := b.(*types.Named); ok {
 			if na.Obj().Name() != nb.Obj().Name() {
@@ -0,0 +1,291 @@
+package modver
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"hash"
+	"reflect"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// compareImpls compares the implementations of obj and newObj,
+// top-level objects in the package at pkgPath
+// whose types are the same.
+// A change to the body of a function or method,
+// to the initializer of a variable that is not part of the public API,
+// or to the value or initializer of a constant that is not,
+// is a Patchlevel change.
+// (Changes to those of public variables and constants
+// are found by compareVarInits and compareConstValues.)
+// Changes to comments and formatting,
+// and renamings of local variables,
+// parameters,
+// and type parameters,
+// do not count.
+func (c *comparer) compareImpls(pkgPath, id string, obj, newObj types.Object) Result {
+	var what string
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() != nil {
+			what = "method"
+		} else {
+			what = "function"
+		}
+
+	case *types.Var:
+		if isPublicObject(c.olderSurface, pkgPath, id, obj) {
+			return None
+		}
+		what = "variable"
+
+	case *types.Const:
+		if isPublicObject(c.olderSurface, pkgPath, id, obj) {
+			return None
+		}
+		if newConst, ok := newObj.(*types.Const); ok {
+			oldVal, newVal := obj.Val(), newConst.Val()
+			if oldVal.Kind() != newVal.Kind() || !constant.Compare(oldVal, token.EQL, newVal) {
+				return c.wrapf(Patchlevel, ImplementationChanged, obj, newObj, "value of constant %s changed from %s to %s", id, oldVal, newVal)
+			}
+		}
+		what = "constant"
+
+	default:
+		return None
+	}
+
+	oldPkg, newPkg := c.older[pkgPath], c.newer[pkgPath]
+	if oldPkg == nil || newPkg == nil {
+		return None
+	}
+	oldFP, ok := implFingerprint(oldPkg, obj)
+	if !ok {
+		return None
+	}
+	newFP, ok := implFingerprint(newPkg, newObj)
+	if !ok || oldFP == newFP {
+		return None
+	}
+	return c.wrapf(Patchlevel, ImplementationChanged, obj, newObj, "implementation of %s %s changed", what, id)
+}
+
+// implFingerprint computes a hash of the normalized syntax of the declaration of obj,
+// a top-level function, method, variable, or constant in pkg.
+// For a variable or constant it covers only the initializer
+// (the type is compared separately).
+// For the function init,
+// of which a package may have several,
+// it covers all of them.
+// It reports false if the declaration cannot be found.
+func implFingerprint(pkg *packages.Package, obj types.Object) ([sha256.Size]byte, bool) {
+	var result [sha256.Size]byte
+
+	fp := &fingerprinter{
+		pkg:    pkg,
+		h:      sha256.New(),
+		locals: make(map[types.Object]int),
+	}
+
+	if fn, ok := obj.(*types.Func); ok && fn.Name() == "init" && fn.Type().(*types.Signature).Recv() == nil {
+		var found bool
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "init" {
+					fp.walk(reflect.ValueOf(fd))
+					found = true
+				}
+			}
+		}
+		if !found {
+			return result, false
+		}
+		copy(result[:], fp.h.Sum(nil))
+		return result, true
+	}
+
+	switch node := findDeclNode(pkg, obj).(type) {
+	case *ast.FuncDecl:
+		fp.walk(reflect.ValueOf(node))
+
+	case *ast.ValueSpec:
+		if init := varInit(node, obj); init != nil {
+			fp.walk(reflect.ValueOf(init))
+		} else {
+			fmt.Fprint(fp.h, "nil;")
+		}
+
+	default:
+		return result, false
+	}
+
+	copy(result[:], fp.h.Sum(nil))
+	return result, true
+}
+
+// compareBlanks compares the blank (_) declarations in oldPkg and newPkg,
+// the older and newer versions of the package at pkgPath.
+// These have no names by which to match older ones with newer ones,
+// so any change to them,
+// taken together,
+// is a Patchlevel change.
+// Changes to comments and formatting do not count.
+func compareBlanks(pkgPath string, oldPkg, newPkg *packages.Package) Result {
+	if blankFingerprint(oldPkg) == blankFingerprint(newPkg) {
+		return None
+	}
+	return inObject(wrapk(Patchlevel, ImplementationChanged, "blank declarations in package %s changed", pkgPath), pkgPath, "")
+}
+
+// blankFingerprint computes a hash of the normalized syntax of the blank declarations in pkg, in order.
+// For a blank variable or constant it covers the type and initializer.
+func blankFingerprint(pkg *packages.Package) [sha256.Size]byte {
+	var result [sha256.Size]byte
+
+	fp := &fingerprinter{
+		pkg:    pkg,
+		h:      sha256.New(),
+		locals: make(map[types.Object]int),
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.Name != "_" {
+								continue
+							}
+							for _, node := range []ast.Expr{spec.Type, varInit(spec, pkg.TypesInfo.Defs[name])} {
+								if node != nil {
+									fp.walk(reflect.ValueOf(node))
+								} else {
+									fmt.Fprint(fp.h, "nil;")
+								}
+							}
+						}
+
+					case *ast.TypeSpec:
+						if spec.Name.Name == "_" {
+							fp.walk(reflect.ValueOf(spec))
+						}
+					}
+				}
+
+			case *ast.FuncDecl:
+				if decl.Name.Name == "_" {
+					fp.walk(reflect.ValueOf(decl))
+				}
+			}
+		}
+	}
+
+	copy(result[:], fp.h.Sum(nil))
+	return result
+}
+
+// fingerprinter hashes a syntax tree,
+// ignoring positions and comments,
+// and replacing the names of objects local to the declaration
+// with their order of appearance.
+type fingerprinter struct {
+	pkg    *packages.Package
+	h      hash.Hash
+	locals map[types.Object]int
+}
+
+var (
+	posType          = reflect.TypeFor[token.Pos]()
+	commentGroupType = reflect.TypeFor[*ast.CommentGroup]()
+	astObjectType    = reflect.TypeFor[*ast.Object]()
+	astScopeType     = reflect.TypeFor[*ast.Scope]()
+)
+
+func (fp *fingerprinter) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(fp.h, "nil;")
+			return
+		}
+		if id, ok := v.Interface().(*ast.Ident); ok {
+			fp.ident(id)
+			return
+		}
+		fp.walk(v.Elem())
+
+	case reflect.Struct:
+		fmt.Fprintf(fp.h, "%s{", v.Type().Name())
+		for i := 0; i < v.NumField(); i++ {
+			switch v.Type().Field(i).Type {
+			case posType, commentGroupType, astObjectType, astScopeType:
+				continue
+			}
+			fp.walk(v.Field(i))
+		}
+		fmt.Fprint(fp.h, "}")
+
+	case reflect.Slice:
+		fmt.Fprintf(fp.h, "[%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			fp.walk(v.Index(i))
+		}
+		fmt.Fprint(fp.h, "]")
+
+	default:
+		fmt.Fprintf(fp.h, "%q;", fmt.Sprint(v.Interface()))
+	}
+}
+
+func (fp *fingerprinter) ident(id *ast.Ident) {
+	obj := fp.pkg.TypesInfo.ObjectOf(id)
+	switch obj := obj.(type) {
+	case nil:
+		// E.g. a struct field name in a composite literal, or a label.
+
+	case *types.PkgName:
+		// The import name does not matter, only the package.
+		fmt.Fprintf(fp.h, "pkg(%q);", obj.Imported().Path())
+		return
+
+	default:
+		if scope := obj.Parent(); scope != nil && scope != fp.pkg.Types.Scope() && scope != types.Universe {
+			n, ok := fp.locals[obj]
+			if !ok {
+				n = len(fp.locals)
+				fp.locals[obj] = n
+			}
+			fmt.Fprintf(fp.h, "local(%d);", n)
+			return
+		}
+	}
+	fmt.Fprintf(fp.h, "id(%q);", id.Name)
+}
+
+// varInit gives the initializer of obj,
+// a variable declared in spec,
+// or nil if it has none.
+// For a variable initialized from a multi-valued expression,
+// as in var a, b = f(),
+// this is the whole expression.
+func varInit(spec *ast.ValueSpec, obj types.Object) ast.Expr {
+	if len(spec.Values) != len(spec.Names) {
+		if len(spec.Values) == 1 {
+			return spec.Values[0]
+		}
+		return nil
+	}
+	for i, name := range spec.Names {
+		if name.Pos() == obj.Pos() {
+			return spec.Values[i]
+		}
+	}
+	return nil
+}
@@ -17,8 +17,11 @@ import (
 )
 
 // PR performs modver analysis on a GitHub pull request.
-func PR(ctx context.Context, gh *github.Client, owner, reponame string, prnum int) (modver.Result, error) {
-	return prHelper(ctx, gh.Repositories, gh.PullRequests, gh.Issues, modver.CompareGit2, owner, reponame, prnum)
+// It uses the given callback function
+// (normally modver.CompareGit2)
+// to compare the base and head of the pull request.
+func PR(ctx context.Context, gh *github.Client, owner, reponame string, prnum int, comparer func(ctx context.Context, baseURL, baseSHA, headURL, headSHA string) (modver.Result, error)) (modver.Result, error) {
+	return prHelper(ctx, gh.Repositories, gh.PullRequests, gh.Issues, comparer, owner, reponame, prnum)
 }
 
 type reposIntf interface {
@@ -115,16 +118,37 @@ var commentTplStr string
 
 var commentTpl = template.Must(template.New("").Parse(commentTplStr))
 
+// commentDecls are the older and newer declarations of a top-level object that changed,
+// for rendering in a PR comment.
+type commentDecls struct {
+	Object       string
+	Older, Newer string
+}
+
 func commentBody(result modver.Result) (string, error) {
 	report := new(bytes.Buffer)
 	modver.Pretty(report, result)
 
+	var decls []commentDecls
+	for _, ch := range result.Changes() {
+		if ch.OldDecl == "" && ch.NewDecl == "" {
+			continue
+		}
+		obj := ch.Object
+		if ch.PkgPath != "" {
+			obj = ch.PkgPath + "." + obj
+		}
+		decls = append(decls, commentDecls{Object: obj, Older: ch.OldDecl, Newer: ch.NewDecl})
+	}
+
 	s := struct {
 		Code   string
 		Report string
+		Decls  []commentDecls
 	}{
 		Code:   result.Code().String(),
 		Report: report.String(),
+		Decls:  decls,
 	}
 
 	out := new(bytes.Buffer)
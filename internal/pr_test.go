@@ -132,3 +132,20 @@ func mockComparer(result modver.Result) func(_ context.Context, _, _, _, _ strin
 func ptr[T any](x T) *T {
 	return &x
 }
+
+func TestCommentBodyDecls(t *testing.T) {
+	res, err := modver.CompareDirs("../testdata/_transitivemajor/a1", "../testdata/_transitivemajor/a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := commentBody(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "<summary><code>foo.bar/a.F</code></summary>") {
+		t.Errorf("no declarations for foo.bar/a.F in comment body:\n%s", body)
+	}
+	if !strings.Contains(body, "```go\nfunc F(val d.D) int\n```") {
+		t.Errorf("no declaration of F in comment body:\n%s", body)
+	}
+}
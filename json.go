@@ -0,0 +1,206 @@
+package modver
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+)
+
+// The JSON encoding of a Results value
+// (and of any other Result that is not a bare ResultCode)
+// looks like this:
+//
+//	{
+//	  "code": "Major",
+//	  "results": [
+//	    {
+//	      "code": "Major",
+//	      "reasons": [
+//	        {
+//	          "kind": "ObjectChanged",
+//	          "code": "Major",
+//	          "pkgpath": "example.com/foo",
+//	          "object": "X",
+//	          "oldpos": {"filename": "/tmp/older/foo.go", "offset": 20, "line": 3, "column": 6},
+//	          "newpos": {"filename": "/tmp/newer/foo.go", "offset": 20, "line": 3, "column": 6},
+//	          "oldtype": "example.com/foo.X",
+//	          "newtype": "example.com/foo.X",
+//	          "olddecl": "type X struct {\n\tA int\n}",
+//	          "newdecl": "type X struct {\n\tA string\n}",
+//	          "reason": "checking X"
+//	        },
+//	        ...
+//	      ]
+//	    },
+//	    ...
+//	  ]
+//	}
+//
+// Each member of "results" is one Result,
+// and its "reasons" are that Result's Changes.
+// A reason has a "proof" field
+// (with "snippet", "proven", and "err" fields, see Proof)
+// when its Change has one.
+// A reason has a "rule" field
+// (the ID of a catalog rule, see LookupRule)
+// when its Change has one.
+
+type (
+	jsonResults struct {
+		Code    ResultCode   `json:"code"`
+		Results []jsonResult `json:"results"`
+	}
+
+	jsonResult struct {
+		Code    ResultCode `json:"code"`
+		Reasons []Change   `json:"reasons"`
+	}
+
+	jsonChange struct {
+		Kind    ChangeKind    `json:"kind"`
+		Rule    string        `json:"rule,omitempty"`
+		Code    ResultCode    `json:"code"`
+		PkgPath string        `json:"pkgpath,omitempty"`
+		Object  string        `json:"object,omitempty"`
+		OldPos  *jsonPosition `json:"oldpos,omitempty"`
+		NewPos  *jsonPosition `json:"newpos,omitempty"`
+		OldType string        `json:"oldtype,omitempty"`
+		NewType string        `json:"newtype,omitempty"`
+		OldDecl string        `json:"olddecl,omitempty"`
+		NewDecl string        `json:"newdecl,omitempty"`
+		Proof   *jsonProof    `json:"proof,omitempty"`
+		Reason  string        `json:"reason"`
+	}
+
+	jsonProof struct {
+		Snippet string `json:"snippet"`
+		Proven  bool   `json:"proven"`
+		Err     string `json:"err,omitempty"`
+	}
+
+	jsonPosition struct {
+		Filename string `json:"filename"`
+		Offset   int    `json:"offset"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	}
+)
+
+// MarshalJSON implements json.Marshaler.
+func (rs Results) MarshalJSON() ([]byte, error) {
+	j := jsonResults{
+		Code:    rs.Code(),
+		Results: make([]jsonResult, 0, len(rs)),
+	}
+	for _, r := range rs {
+		reasons := r.Changes()
+		if reasons == nil {
+			reasons = []Change{}
+		}
+		j.Results = append(j.Results, jsonResult{Code: r.Code(), Reasons: reasons})
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It reconstructs each member of rs from its chain of Changes.
+func (rs *Results) UnmarshalJSON(data []byte) error {
+	var j jsonResults
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	result := make(Results, 0, len(j.Results))
+	for _, jr := range j.Results {
+		var r Result = jr.Code
+		for i := len(jr.Reasons) - 1; i >= 0; i-- {
+			ch := jr.Reasons[i]
+			reason := ch.Reason
+			ch.Code, ch.Reason = 0, ""
+			r = wrapped{r: r, change: ch, whyfmt: "%s", whyargs: []any{reason}}
+		}
+		result = append(result, r)
+	}
+	*rs = result
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// A wrapped Result is encoded as a Results value with one member.
+func (w wrapped) MarshalJSON() ([]byte, error) {
+	return Results{w}.MarshalJSON()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (ch Change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonChange{
+		Kind:    ch.Kind,
+		Rule:    ch.Rule,
+		Code:    ch.Code,
+		PkgPath: ch.PkgPath,
+		Object:  ch.Object,
+		OldPos:  toJSONPosition(ch.OldPos),
+		NewPos:  toJSONPosition(ch.NewPos),
+		OldType: ch.OldType,
+		NewType: ch.NewType,
+		OldDecl: ch.OldDecl,
+		NewDecl: ch.NewDecl,
+		Proof:   (*jsonProof)(ch.Proof),
+		Reason:  ch.Reason,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ch *Change) UnmarshalJSON(data []byte) error {
+	var j jsonChange
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*ch = Change{
+		Kind:    j.Kind,
+		Rule:    j.Rule,
+		Code:    j.Code,
+		PkgPath: j.PkgPath,
+		Object:  j.Object,
+		OldPos:  fromJSONPosition(j.OldPos),
+		NewPos:  fromJSONPosition(j.NewPos),
+		OldType: j.OldType,
+		NewType: j.NewType,
+		OldDecl: j.OldDecl,
+		NewDecl: j.NewDecl,
+		Proof:   (*Proof)(j.Proof),
+		Reason:  j.Reason,
+	}
+	return nil
+}
+
+func toJSONPosition(pos token.Position) *jsonPosition {
+	if !pos.IsValid() {
+		return nil
+	}
+	return &jsonPosition{Filename: pos.Filename, Offset: pos.Offset, Line: pos.Line, Column: pos.Column}
+}
+
+func fromJSONPosition(pos *jsonPosition) token.Position {
+	if pos == nil {
+		return token.Position{}
+	}
+	return token.Position{Filename: pos.Filename, Offset: pos.Offset, Line: pos.Line, Column: pos.Column}
+}
+
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	if k < 0 || k >= numChangeKinds {
+		return nil, fmt.Errorf("unknown ChangeKind value %d", k)
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *ChangeKind) UnmarshalText(text []byte) error {
+	for i, name := range changeKindNames {
+		if name == string(text) {
+			*k = ChangeKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown ChangeKind value %q", text)
+}
@@ -0,0 +1,79 @@
+package modver
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResultsJSON(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "multiple", func(olderTestDir, newerTestDir string) {
+		res, err := CompareDirsAll(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		j, err := json.Marshal(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got Results
+		if err := json.Unmarshal(j, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Code() != res.Code() {
+			t.Errorf("got code %s, want %s", got.Code(), res.Code())
+		}
+		if got.String() != res.String() {
+			t.Errorf("got %s, want %s", got, res)
+		}
+		if !reflect.DeepEqual(got.Changes(), res.Changes()) {
+			t.Errorf("got changes %v, want %v", got.Changes(), res.Changes())
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBareResultCodeJSON(t *testing.T) {
+	j, err := json.Marshal(Results{Minor})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Results
+	if err := json.Unmarshal(j, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != Minor {
+		t.Errorf("got %v, want [Minor]", got)
+	}
+}
+
+func TestChangeKindText(t *testing.T) {
+	for k := OtherChange; k < numChangeKinds; k++ {
+		text, err := k.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got ChangeKind
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+		if got != k {
+			t.Errorf("got %s, want %s", got, k)
+		}
+	}
+
+	if _, err := numChangeKinds.MarshalText(); err == nil {
+		t.Error("got no error marshaling out-of-range ChangeKind")
+	}
+	var k ChangeKind
+	if err := k.UnmarshalText([]byte("Bogus")); err == nil {
+		t.Error("got no error unmarshaling unknown ChangeKind")
+	}
+}
@@ -0,0 +1,133 @@
+package modver
+
+import "go/types"
+
+// compareObjectKinds compares the kinds of obj and newObj,
+// two versions of the same top-level object:
+// constant, variable, function, defined type, or alias.
+// It also compares constants for whether they are typed or untyped.
+// It reports false if there is no such change to report,
+// in which case the objects' types should be compared instead.
+func (c *comparer) compareObjectKinds(obj, newObj types.Object) (Result, bool) {
+	name := obj.Name()
+
+	switch obj := obj.(type) {
+	case *types.Const:
+		switch newObj := newObj.(type) {
+		case *types.Const:
+			return c.compareConstTypedness(obj, newObj)
+		case *types.Var:
+			return c.wrapf(Major, ObjectKindChanged, obj, newObj, "%s changed from a constant to a variable, which cannot be used in constant expressions such as array lengths", name), true
+		}
+
+	case *types.Var:
+		switch newObj := newObj.(type) {
+		case *types.Var:
+			return nil, false
+		case *types.Const:
+			return c.wrapf(Major, ObjectKindChanged, obj, newObj, "%s changed from a variable to a constant, which cannot be assigned to or have its address taken", name), true
+		case *types.Func:
+			if _, ok := obj.Type().Underlying().(*types.Signature); ok {
+				return c.wrapf(Major, ObjectKindChanged, obj, newObj, "%s changed from a variable of function type to a function, which cannot be assigned to or have its address taken", name), true
+			}
+		}
+
+	case *types.Func:
+		switch newObj := newObj.(type) {
+		case *types.Func:
+			return nil, false
+		case *types.Var:
+			if _, ok := newObj.Type().Underlying().(*types.Signature); ok {
+				if r := c.compareTypes(obj.Type(), newObj.Type()); r.Code() == Major {
+					return c.wrapf(r, ObjectKindChanged, obj, newObj, "%s changed from a function to a variable of function type", name), true
+				}
+				return c.wrapf(Minor, FuncBecameVar, obj, newObj, "%s changed from a function to a variable of function type, which can be assigned to", name), true
+			}
+		}
+
+	case *types.TypeName:
+		newTN, ok := newObj.(*types.TypeName)
+		if !ok {
+			break
+		}
+		switch {
+		case obj.IsAlias() == newTN.IsAlias():
+			return nil, false
+
+		case newTN.IsAlias():
+			if r := c.compareTypeParamLists(typeParams(obj.Type()), typeParams(newTN.Type())); r.Code() == Major {
+				return c.wrapf(r, TypeParamsChanged, obj, newObj, "in type parameters of %s, which changed from a defined type to an alias", name), true
+			}
+			target := types.Unalias(newTN.Type())
+			if _, ok := target.(*types.Named); ok {
+				// An alias for another defined type
+				// (perhaps the same one, moved to another package).
+				// This is a matter for type comparison.
+				return nil, false
+			}
+			if r := c.compareTypes(obj.Type().Underlying(), target); r.Code() == Major {
+				return c.wrapf(r, ObjectKindChanged, obj, newObj, "%s changed from a defined type to an alias for %s", name, target), true
+			}
+			return c.wrapf(c.level(TypeBecameAlias, Minor), TypeBecameAlias, obj, newObj, "%s changed from a defined type to an alias for %s, so it is now identical to %s", name, target, target), true
+
+		default:
+			target := types.Unalias(obj.Type())
+			return c.wrapf(Major, ObjectKindChanged, obj, newObj, "%s changed from an alias for %s to a defined type, so it is no longer identical to %s", name, target, target), true
+		}
+	}
+
+	return c.wrapf(Major, ObjectKindChanged, obj, newObj, "%s changed from %s to %s", name, objectKind(obj), objectKind(newObj)), true
+}
+
+// compareConstTypedness compares constants obj and newObj
+// for whether they are typed or untyped.
+// It reports false if neither of them changed,
+// in which case their types should be compared instead.
+func (c *comparer) compareConstTypedness(obj, newObj *types.Const) (Result, bool) {
+	var (
+		typ, newTyp = obj.Type(), newObj.Type()
+		name        = obj.Name()
+	)
+
+	switch untyped, newUntyped := isUntyped(typ), isUntyped(newTyp); {
+	case untyped == newUntyped:
+		return nil, false
+
+	case untyped:
+		return c.wrapf(Major, ConstTypednessChanged, obj, newObj, "constant %s changed from %s to type %s, so it can no longer be used as a value of other types", name, typ, newTyp), true
+
+	default:
+		def := types.Default(newTyp)
+		if c.identical(typ, def) {
+			return c.wrapf(Minor, ConstTypednessChanged, obj, newObj, "constant %s changed from type %s to %s, whose default type is the same", name, typ, newTyp), true
+		}
+		return c.wrapf(Major, ConstTypednessChanged, obj, newObj, "constant %s changed from type %s to %s, whose default type is %s", name, typ, newTyp, def), true
+	}
+}
+
+func isUntyped(typ types.Type) bool {
+	b, ok := typ.(*types.Basic)
+	return ok && b.Info()&types.IsUntyped != 0
+}
+
+// objectKind describes the kind of the top-level object obj,
+// e.g. "a constant".
+func objectKind(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Const:
+		return "a constant"
+	case *types.Var:
+		return "a variable"
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() != nil {
+			return "a method"
+		}
+		return "a function"
+	case *types.TypeName:
+		if obj.IsAlias() {
+			return "an alias"
+		}
+		return "a defined type"
+	}
+	return "an object"
+}
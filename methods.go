@@ -0,0 +1,305 @@
+package modver
+
+import (
+	"go/types"
+	"strings"
+)
+
+// matchRecvChanges pairs the methods in topObjs and newTopObjs
+// (as produced by makeTopObjs)
+// whose receivers changed between pointer and value,
+// and whose keys therefore changed between "*T.M" and "T.M".
+// The result maps the old key of each such method to its new one.
+func matchRecvChanges(topObjs, newTopObjs map[string]types.Object) map[string]string {
+	result := make(map[string]string)
+	for id, obj := range topObjs {
+		if _, ok := newTopObjs[id]; ok {
+			continue
+		}
+		if _, ok := obj.(*types.Func); !ok || !strings.Contains(id, ".") {
+			continue
+		}
+		var alt string
+		if strings.HasPrefix(id, "*") {
+			alt = id[1:]
+		} else {
+			alt = "*" + id
+		}
+		if _, ok := topObjs[alt]; ok {
+			continue
+		}
+		if _, ok := newTopObjs[alt]; ok {
+			result[id] = alt
+		}
+	}
+	return result
+}
+
+// compareRecvs compares the receivers of obj and newObj,
+// if they are methods,
+// by the method sets of their receivers' base types.
+// Changing a pointer receiver to a value receiver adds the method to the value type's method set,
+// which is a Minor change.
+// Changing a value receiver to a pointer receiver removes it,
+// which is a Major change:
+// values of the type no longer have the method,
+// and may no longer satisfy interfaces that require it.
+func (c *comparer) compareRecvs(obj, newObj types.Object) Result {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return None
+	}
+	newFn, ok := newObj.(*types.Func)
+	if !ok {
+		return None
+	}
+	named, newNamed := recvNamed(fn), recvNamed(newFn)
+	if named == nil || newNamed == nil {
+		return None
+	}
+
+	var (
+		inValueSet    = types.NewMethodSet(named).Lookup(fn.Pkg(), fn.Name()) != nil
+		newInValueSet = types.NewMethodSet(newNamed).Lookup(newFn.Pkg(), newFn.Name()) != nil
+		typeName      = named.Obj().Name()
+	)
+
+	switch {
+	case inValueSet && !newInValueSet:
+		return c.wrapf(Major, ReceiverChanged, obj, newObj, "method %s changed from a value receiver to a pointer receiver, so it is in the method set of *%s but no longer of %s", fn.Name(), typeName, typeName)
+	case !inValueSet && newInValueSet:
+		return c.wrapf(Minor, ReceiverChanged, obj, newObj, "method %s changed from a pointer receiver to a value receiver, so it is now in the method set of %s as well as *%s", fn.Name(), typeName, typeName)
+	}
+	return None
+}
+
+// recvNamed gives the named type that fn,
+// a method,
+// is declared on,
+// or nil if fn is not a method.
+func recvNamed(fn *types.Func) *types.Named {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	typ := recv.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, _ := typ.(*types.Named)
+	return named
+}
+
+// compareMethodSets compares the complete method sets of obj and newObj,
+// if they are non-interface named types,
+// and their sets of promoted fields.
+// Methods and fields declared directly on the types are compared elsewhere;
+// this finds changes in what is promoted from embedded types,
+// including embedded types from other modules.
+// Losing a promoted method or field is a Major change,
+// gaining one is Minor,
+// and a change in the type of one is as for any other type.
+func (c *comparer) compareMethodSets(obj, newObj types.Object) Result {
+	if _, ok := obj.(*types.TypeName); !ok {
+		return None
+	}
+	if _, ok := newObj.(*types.TypeName); !ok {
+		return None
+	}
+	named, ok := types.Unalias(obj.Type()).(*types.Named)
+	if !ok || types.IsInterface(named) {
+		return None
+	}
+	newNamed, ok := types.Unalias(newObj.Type()).(*types.Named)
+	if !ok || types.IsInterface(newNamed) {
+		return None
+	}
+
+	var (
+		valueSet           = types.NewMethodSet(named)
+		ptrSet             = types.NewMethodSet(types.NewPointer(named))
+		newValueSet        = types.NewMethodSet(newNamed)
+		newPtrSet          = types.NewMethodSet(types.NewPointer(newNamed))
+		typeName           = named.Obj().Name()
+		res         Result = None
+	)
+
+	for _, name := range exportedMethodNames(ptrSet, newPtrSet) {
+		var (
+			val, ptr       = valueSet.Lookup(nil, name), ptrSet.Lookup(nil, name)
+			newVal, newPtr = newValueSet.Lookup(nil, name), newPtrSet.Lookup(nil, name)
+		)
+		if !isPromoted(ptr) && !isPromoted(newPtr) {
+			continue
+		}
+
+		var r Result = None
+		switch {
+		case ptr == nil:
+			r = c.wrapf(c.level(PromotionAdded, Minor), PromotionAdded, nil, newPtr.Obj(), "promoted method %s was added to the method set of %s", name, methodSetName(typeName, newVal != nil))
+		case newPtr == nil:
+			if isPromoted(ptr) {
+				r = c.wrapf(Major, PromotionRemoved, ptr.Obj(), nil, "promoted method %s was removed from the method set of %s", name, methodSetName(typeName, val != nil))
+			}
+		default:
+			r = c.compareTypes(ptr.Obj().Type(), newPtr.Obj().Type())
+			r = c.wrapf(r, PromotedChanged, ptr.Obj(), newPtr.Obj(), "in promoted method %s of %s", name, typeName)
+			if r.Code() == Major {
+				break
+			}
+			switch {
+			case val != nil && newVal == nil:
+				r = c.wrapf(Major, PromotionRemoved, val.Obj(), nil, "promoted method %s was removed from the method set of %s (but not *%s)", name, typeName, typeName)
+			case val == nil && newVal != nil && r.Code() < Minor:
+				r = c.wrapf(c.level(PromotionAdded, Minor), PromotionAdded, nil, newVal.Obj(), "promoted method %s was added to the method set of %s (not only *%s)", name, typeName, typeName)
+			}
+		}
+
+		if r.Code() > res.Code() {
+			res = r
+			if res.Code() == Major {
+				return res
+			}
+		}
+	}
+
+	var (
+		fields    = promotedFields(named)
+		newFields = promotedFields(newNamed)
+	)
+	for _, name := range sortedKeys(fields, newFields) {
+		field, newField := fields[name], newFields[name]
+
+		var r Result = None
+		switch {
+		case field == nil:
+			if obj, _, _ := types.LookupFieldOrMethod(named, true, nil, name); obj == nil {
+				r = c.wrapf(c.level(PromotionAdded, Minor), PromotionAdded, nil, newField, "promoted field %s was added to %s", name, typeName)
+			}
+		case newField == nil:
+			switch obj, _, _ := types.LookupFieldOrMethod(newNamed, true, nil, name); obj := obj.(type) {
+			case *types.Var:
+				// The field is now declared directly in newNamed.
+				r = c.wrapf(c.compareTypes(field.Type(), obj.Type()), PromotedChanged, field, obj, "in promoted field %s of %s", name, typeName)
+			default:
+				r = c.wrapf(Major, PromotionRemoved, field, nil, "promoted field %s was removed from %s", name, typeName)
+			}
+		default:
+			r = c.wrapf(c.compareTypes(field.Type(), newField.Type()), PromotedChanged, field, newField, "in promoted field %s of %s", name, typeName)
+		}
+
+		if r.Code() > res.Code() {
+			res = r
+			if res.Code() == Major {
+				return res
+			}
+		}
+	}
+
+	return res
+}
+
+// isPromotedInNewer tells whether obj,
+// an exported method declared in the older version of the package at pkgPath,
+// is promoted from an embedded type in the newer version,
+// so that it is still in the method set of its receiver type
+// (which compareMethodSets checks).
+func (c *comparer) isPromotedInNewer(pkgPath string, obj types.Object) bool {
+	fn, ok := obj.(*types.Func)
+	if !ok || !fn.Exported() {
+		return false
+	}
+	named := recvNamed(fn)
+	if named == nil {
+		return false
+	}
+	newPkg := c.newer[pkgPath]
+	if newPkg == nil {
+		return false
+	}
+	tn, ok := newPkg.Types.Scope().Lookup(named.Obj().Name()).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	newNamed, ok := types.Unalias(tn.Type()).(*types.Named)
+	if !ok {
+		return false
+	}
+	return isPromoted(types.NewMethodSet(types.NewPointer(newNamed)).Lookup(nil, fn.Name()))
+}
+
+// exportedMethodNames gives the sorted names of the exported methods in the given method sets.
+func exportedMethodNames(msets ...*types.MethodSet) []string {
+	names := make(map[string]bool)
+	for _, mset := range msets {
+		for i := 0; i < mset.Len(); i++ {
+			if obj := mset.At(i).Obj(); obj.Exported() {
+				names[obj.Name()] = true
+			}
+		}
+	}
+	return sortedKeys(names)
+}
+
+// methodSetName describes the method set of the named type typeName:
+// that of typeName itself if inValueSet is true
+// (implying that of *typeName too),
+// otherwise that of *typeName.
+func methodSetName(typeName string, inValueSet bool) string {
+	if inValueSet {
+		return typeName
+	}
+	return "*" + typeName
+}
+
+// isPromoted tells whether sel,
+// an entry in a method set,
+// is for a method promoted from an embedded type.
+func isPromoted(sel *types.Selection) bool {
+	return sel != nil && len(sel.Index()) > 1
+}
+
+// promotedFields gives the exported fields that are promoted to named
+// (a struct type)
+// from its embedded types,
+// keyed by name.
+// Fields that are shadowed or ambiguous are not included.
+func promotedFields(named *types.Named) map[string]*types.Var {
+	var (
+		names = make(map[string]bool)
+		seen  = make(map[types.Type]bool)
+		walk  func(types.Type, int)
+	)
+	walk = func(typ types.Type, depth int) {
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			return
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if depth > 0 && f.Exported() {
+				names[f.Name()] = true
+			}
+			if f.Embedded() {
+				walk(f.Type(), depth+1)
+			}
+		}
+	}
+	walk(named, 0)
+
+	result := make(map[string]*types.Var)
+	for name := range names {
+		obj, index, _ := types.LookupFieldOrMethod(named, true, nil, name)
+		if v, ok := obj.(*types.Var); ok && v.IsField() && len(index) > 1 {
+			result[name] = v
+		}
+	}
+	return result
+}
@@ -9,11 +9,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/bobg/errors"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestCompare(t *testing.T) {
@@ -69,7 +71,11 @@ func runtest(tb testing.TB, typ string, want ResultCode) {
 		}
 		name := strings.TrimSuffix(entry.Name(), ".tmpl")
 		tbRun(tb, fmt.Sprintf("%s/%s", typ, name), func(tb testing.TB) {
-			err := withTestDirs(tree, name, func(olderTestDir, newerTestDir string) {
+			wants, err := readWants(filepath.Join(tree, entry.Name()))
+			if err != nil {
+				tb.Fatal(err)
+			}
+			err = withTestDirs(tree, name, func(olderTestDir, newerTestDir string) {
 				if b != nil {
 					b.ResetTimer()
 					for i := 0; i < b.N; i++ {
@@ -90,6 +96,19 @@ func runtest(tb testing.TB, typ string, want ResultCode) {
 				} else if testing.Verbose() {
 					tb.Log(got)
 				}
+
+				if len(wants) == 0 {
+					return
+				}
+				all, err := CompareDirsAll(olderTestDir, newerTestDir)
+				if err != nil {
+					tb.Fatal(err)
+				}
+				for _, w := range wants {
+					if !w.foundIn(all) {
+						tb.Errorf("%s: no result matching %q in %s", name, w, all)
+					}
+				}
 			})
 			if err != nil {
 				tb.Fatal(err)
@@ -98,6 +117,141 @@ func runtest(tb testing.TB, typ string, want ResultCode) {
 	}
 }
 
+// wantResult is a result expected from comparing the older and newer versions in a testdata template,
+// given in the template with a line like
+//
+//	// want MV304: value of constant X changed from 7 to 8
+//
+// meaning that one of the results from CompareAll has a description containing the text after the colon,
+// and an innermost change with the given rule.
+// The rule may be omitted,
+// as in
+//
+//	// want: value of constant X changed
+type wantResult struct {
+	rule, msg string
+}
+
+var wantRE = regexp.MustCompile(`^// want( MV[0-9]+)?: (.+)$`)
+
+// readWants reads the wantResults in the template file filename.
+func readWants(filename string) ([]wantResult, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var wants []wantResult
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := wantRE.FindStringSubmatch(line); m != nil {
+			wants = append(wants, wantResult{rule: strings.TrimSpace(m[1]), msg: m[2]})
+		}
+	}
+	return wants, nil
+}
+
+// foundIn tells whether any of the results in res matches w.
+func (w wantResult) foundIn(res Result) bool {
+	results, ok := res.(Results)
+	if !ok {
+		results = Results{res}
+	}
+	for _, r := range results {
+		if !strings.Contains(r.String(), w.msg) {
+			continue
+		}
+		if w.rule == "" {
+			return true
+		}
+		if changes := r.Changes(); len(changes) > 0 && changes[len(changes)-1].Rule == w.rule {
+			return true
+		}
+	}
+	return false
+}
+
+func (w wantResult) String() string {
+	if w.rule == "" {
+		return w.msg
+	}
+	return w.rule + ": " + w.msg
+}
+
+// compareCase runs a subtest named dir/name
+// that loads the older and newer versions of the module in the template testdata/dir/name.tmpl
+// and calls f with them.
+// Tests of the result level,
+// and of the rules and descriptions of the results
+// (see wantResult),
+// belong in the testdata directory for that level,
+// where TestCompare runs them;
+// compareCase is for levels that depend on Options,
+// and for other assertions.
+func compareCase(t *testing.T, dir, name string, f func(t *testing.T, olders, newers []*packages.Package)) {
+	t.Run(dir+"/"+name, func(t *testing.T) {
+		err := withTestDirs(filepath.Join("testdata", dir), name, func(olderTestDir, newerTestDir string) {
+			olders, newers, err := LoadDirs(olderTestDir, newerTestDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f(t, olders, newers)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// checkChange checks that the innermost change in res has the rule wantRule
+// (unless that is ""),
+// and that the description of res contains wantMsg.
+func checkChange(t *testing.T, res Result, wantRule, wantMsg string) {
+	t.Helper()
+
+	changes := res.Changes()
+	if len(changes) == 0 {
+		t.Fatalf("no changes in %s", res)
+	}
+	if wantRule != "" {
+		if got := changes[len(changes)-1].Rule; got != wantRule {
+			t.Errorf("got rule %s, want %s", got, wantRule)
+		}
+	}
+	if s := res.String(); !strings.Contains(s, wantMsg) {
+		t.Errorf("got %q, want it to contain %q", s, wantMsg)
+	}
+}
+
+func TestCompareAll(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "multiple", func(olderTestDir, newerTestDir string) {
+		res, err := CompareDirsAll(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Code() != Major {
+			t.Errorf("got %s, want Major", res.Code())
+		}
+
+		results, ok := res.(Results)
+		if !ok {
+			t.Fatalf("got %T, want Results", res)
+		}
+
+		// F is Major, G and X are Minor, unexp is Patchlevel, V is unchanged.
+		want := []ResultCode{Major, Minor, Minor, Patchlevel}
+		if len(results) != len(want) {
+			t.Fatalf("got %d results, want %d: %s", len(results), len(want), results)
+		}
+		for i, r := range results {
+			if r.Code() != want[i] {
+				t.Errorf("result %d: got %s, want %s", i, r, want[i])
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func withTestDirs(tree, name string, f func(olderTestDir, newerTestDir string)) error {
 	tmpls, err := template.ParseFiles(filepath.Join(tree, name+".tmpl"))
 	if err != nil {
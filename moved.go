@@ -0,0 +1,95 @@
+package modver
+
+import "go/types"
+
+// forwardedType gives the named type to which the newer version of the package at pkgPath
+// forwards the name typeName with an alias,
+// as when a type is moved to another package
+// and the old package keeps type T = newpkg.T.
+// It gives nil if there is no such alias,
+// or if its target is in the same package.
+func (c *comparer) forwardedType(pkgPath, typeName string) *types.Named {
+	newPkg := c.newer[pkgPath]
+	if newPkg == nil {
+		return nil
+	}
+	tn, ok := newPkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || !tn.IsAlias() {
+		return nil
+	}
+	named, ok := types.Unalias(tn.Type()).(*types.Named)
+	if !ok {
+		return nil
+	}
+	if pkg := named.Obj().Pkg(); pkg == nil || pkg.Path() == pkgPath {
+		return nil
+	}
+	return named
+}
+
+// isForwarded tells whether older,
+// a named type in the older version of its package,
+// moved to become newer,
+// a named type in another package,
+// leaving a forwarding alias behind.
+func (c *comparer) isForwarded(older, newer *types.Named) bool {
+	pkg := older.Obj().Pkg()
+	if pkg == nil {
+		return false
+	}
+	fwd := c.forwardedType(pkg.Path(), older.Obj().Name())
+	return fwd != nil && fwd.Origin() == newer.Origin()
+}
+
+// compareMoved compares obj and newObj,
+// a defined type in the package at pkgPath and its newer version,
+// for whether the type moved to another package behind a forwarding alias.
+// Clients that use the old name still compile,
+// so the move itself is a Patchlevel change.
+// Changes in the shape of the moved type are found by comparing the types.
+func (c *comparer) compareMoved(pkgPath string, obj, newObj types.Object) Result {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return None
+	}
+	newTN, ok := newObj.(*types.TypeName)
+	if !ok || !newTN.IsAlias() {
+		return None
+	}
+	fwd := c.forwardedType(pkgPath, newTN.Name())
+	if fwd == nil {
+		return None
+	}
+	return c.wrapf(Patchlevel, TypeMoved, obj, fwd, "type %s moved to package %s as %s, leaving a forwarding alias", obj.Name(), fwd.Obj().Pkg().Path(), fwd.Obj().Name())
+}
+
+// matchMovedMethods finds the methods in topObjs
+// (as produced by makeTopObjs for the older version of the package at pkgPath)
+// that are absent from newTopObjs
+// because their receiver types moved to other packages behind forwarding aliases.
+// The result maps the key of each such method to the same method of the moved type.
+func (c *comparer) matchMovedMethods(pkgPath string, topObjs, newTopObjs map[string]types.Object) map[string]types.Object {
+	result := make(map[string]types.Object)
+	for id, obj := range topObjs {
+		if _, ok := newTopObjs[id]; ok {
+			continue
+		}
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		named := recvNamed(fn)
+		if named == nil {
+			continue
+		}
+		fwd := c.forwardedType(pkgPath, named.Obj().Name())
+		if fwd == nil {
+			continue
+		}
+		newFn, _, _ := types.LookupFieldOrMethod(types.NewPointer(fwd), false, fwd.Obj().Pkg(), fn.Name())
+		if newFn, ok := newFn.(*types.Func); ok && recvNamed(newFn) != nil && recvNamed(newFn).Origin() == fwd.Origin() {
+			result[id] = newFn
+		}
+	}
+	return result
+}
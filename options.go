@@ -0,0 +1,90 @@
+package modver
+
+// Option is an option to Compare or CompareAll.
+type Option func(*comparer)
+
+// WithProfile sets the Profile that Compare and CompareAll use.
+// The default is Lenient.
+// Levels set with other Options take precedence over the profile.
+func WithProfile(p Profile) Option {
+	return func(c *comparer) {
+		c.profile = p
+	}
+}
+
+// WithConstLevel sets the level at which Compare and CompareAll report a change in the value of an exported constant
+// that is not part of an enum-like block
+// (see WithEnumLevel).
+// The default is Patchlevel
+// (or Major under the Go1Compat and Strict profiles).
+// Use None to ignore such changes.
+func WithConstLevel(code ResultCode) Option {
+	return func(c *comparer) {
+		c.levels[ConstValueChanged] = code
+	}
+}
+
+// WithEnumLevel sets the level at which Compare and CompareAll report a change in the value of an exported constant
+// that is part of an enum-like block:
+// a const declaration group that uses iota.
+// Inserting, removing, or reordering the constants in such a block
+// changes the values of the ones after it.
+// The default is Major.
+// Use None to ignore such changes.
+func WithEnumLevel(code ResultCode) Option {
+	return func(c *comparer) {
+		c.levels[EnumValueChanged] = code
+	}
+}
+
+// WithVarInitLevel sets the level at which Compare and CompareAll report a change in the initializer of an exported variable,
+// such as a change from
+//
+//	var DefaultTimeout = 5 * time.Second
+//
+// to
+//
+//	var DefaultTimeout = 10 * time.Second
+//
+// The default is Patchlevel.
+// Use None to ignore such changes.
+func WithVarInitLevel(code ResultCode) Option {
+	return func(c *comparer) {
+		c.levels[VarInitChanged] = code
+	}
+}
+
+// WithInterfaceRole sets the role of the interface type named typeName
+// in the package at pkgPath,
+// overriding the role that Compare and CompareAll infer from where the interface appears in the public API
+// (see InterfaceRole).
+// Adding a method to an interface that clients can implement is a Major change;
+// adding one to an interface that clients only consume is Minor
+// (or Major under the Go1Compat and Strict profiles).
+func WithInterfaceRole(pkgPath, typeName string, role InterfaceRole) Option {
+	return func(c *comparer) {
+		c.interfaceRoleOverrides[typeKey{pkgPath: pkgPath, name: typeName}] = role
+	}
+}
+
+// WithTagComparer sets the TagComparer that Compare and CompareAll use
+// for the values of key in the struct tags of named struct types,
+// replacing any built-in one.
+// (Any change in the tags of an unnamed struct type makes it a different type,
+// which is Major.)
+// There are built-in TagComparers for the json, yaml, xml, and protobuf keys,
+// which understand the name and options in their values:
+// a change in the name that a field is encoded with is Major,
+// while adding an option like omitempty is Patchlevel,
+// and reordering the options is no change.
+// A nil tc removes the TagComparer for key,
+// so that any change in its values is Major.
+func WithTagComparer(key string, tc TagComparer) Option {
+	return func(c *comparer) {
+		if tc == nil {
+			delete(c.tagComparers, key)
+		} else {
+			c.tagComparers[key] = tc
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package modver
+
+import "fmt"
+
+// Profile is a set of rules that Compare and CompareAll apply
+// when deciding the level of some kinds of change.
+// See WithProfile.
+type Profile int
+
+// Values for Profile.
+const (
+	// Lenient is the default profile.
+	// It requires the lowest version bump that existing clients
+	// using the API in the ordinary ways can rely on.
+	Lenient Profile = iota
+
+	// Go1Compat follows the rules that the Go team applies to the standard library
+	// under the Go 1 compatibility promise
+	// (https://go.dev/doc/go1compat),
+	// as enforced by its API checker.
+	// It is like Lenient,
+	// except that a function or method may not change its signature at all,
+	// even in ways that leave existing calls valid
+	// (such as adding optional variadic parameters),
+	// since that breaks clients that use it as a function value;
+	// the value of an exported constant may not change;
+	// and a method may not be added to an exported interface
+	// even if clients only consume it
+	// (see InterfaceRole),
+	// since they may still implement it,
+	// e.g. in tests.
+	// As with Lenient,
+	// adding struct fields and methods is a Minor change:
+	// the promise excludes clients that use unkeyed struct literals,
+	// or that embed types in ways that make new selectors ambiguous.
+	Go1Compat
+
+	// Strict is like Go1Compat,
+	// but also treats as Major the changes that can break clients
+	// in ways that the Go 1 compatibility promise excludes:
+	// adding an exported struct field,
+	// or an unexported one to a struct whose fields were all exported,
+	// which breaks unkeyed struct literals;
+	// adding a method or promoted method or field to a type,
+	// which can make selectors ambiguous in clients that embed the type alongside another;
+	// and changing a defined type to an alias for an unnamed type,
+	// which makes type switches that list both types invalid.
+	Strict
+)
+
+// String returns the name of p.
+func (p Profile) String() string {
+	switch p {
+	case Lenient:
+		return "lenient"
+	case Go1Compat:
+		return "go1compat"
+	case Strict:
+		return "strict"
+	default:
+		return fmt.Sprintf("Profile(%d)", int(p))
+	}
+}
+
+func (p Profile) MarshalText() ([]byte, error) {
+	switch p {
+	case Lenient, Go1Compat, Strict:
+		return []byte(p.String()), nil
+	}
+	return nil, fmt.Errorf("unknown Profile value %d", p)
+}
+
+func (p *Profile) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "lenient":
+		*p = Lenient
+	case "go1compat":
+		*p = Go1Compat
+	case "strict":
+		*p = Strict
+	default:
+		return fmt.Errorf("unknown Profile value %q", text)
+	}
+	return nil
+}
+
+// level gives the level, under profile p,
+// of a change of the given kind
+// whose level is otherwise code.
+func (p Profile) level(kind ChangeKind, code ResultCode) ResultCode {
+	if p >= Go1Compat {
+		switch kind {
+		case OptionalParamsAdded, ChanDirRestricted, ConstValueChanged, ConsumerOnlyMethodsAdded:
+			return Major
+		}
+	}
+	if p >= Strict {
+		switch kind {
+		case FieldAdded, MethodAdded, PromotionAdded, TypeBecameAlias:
+			return Major
+		}
+	}
+	return code
+}
+
+// level gives the level of a change of the given kind
+// whose level is otherwise code,
+// after applying any overriding Options.
+func (c *comparer) level(kind ChangeKind, code ResultCode) ResultCode {
+	if code, ok := c.levels[kind]; ok {
+		return code
+	}
+	return c.profile.level(kind, code)
+}
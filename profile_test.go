@@ -0,0 +1,87 @@
+package modver
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestProfiles(t *testing.T) {
+	// The levels of these cases without options are checked by TestCompare.
+	cases := []struct {
+		dir, name string
+		opts      []Option
+		want      ResultCode
+		wantRule  string
+	}{{
+		dir: "minor", name: "addfield",
+		opts: []Option{WithProfile(Go1Compat)},
+		want: Minor,
+	}, {
+		dir: "minor", name: "addfield",
+		opts:     []Option{WithProfile(Strict)},
+		want:     Major,
+		wantRule: "MV121",
+	}, {
+		dir: "patchlevel", name: "addunexportedfield",
+		opts:     []Option{WithProfile(Strict)},
+		want:     Major,
+		wantRule: "MV121",
+	}, {
+		dir: "patchlevel", name: "addsecondunexportedfield",
+		opts: []Option{WithProfile(Strict)},
+		want: Patchlevel,
+	}, {
+		dir: "minor", name: "familiarmethodname",
+		opts:     []Option{WithProfile(Strict)},
+		want:     Major,
+		wantRule: "MV122",
+	}, {
+		dir: "minor", name: "addoptparam",
+		opts:     []Option{WithProfile(Go1Compat)},
+		want:     Major,
+		wantRule: "MV124",
+	}, {
+		dir: "patchlevel", name: "chconstant",
+		opts:     []Option{WithProfile(Go1Compat)},
+		want:     Major,
+		wantRule: "MV126",
+	}, {
+		dir: "patchlevel", name: "chconstant",
+		opts: []Option{WithConstLevel(Patchlevel), WithProfile(Go1Compat)},
+		want: Patchlevel,
+	}}
+
+	for _, tc := range cases {
+		compareCase(t, tc.dir, tc.name, func(t *testing.T, olders, newers []*packages.Package) {
+			res := CompareAll(olders, newers, tc.opts...)
+			if got := res.Code(); got != tc.want {
+				t.Errorf("with %d option(s): got %s, want %s", len(tc.opts), got, tc.want)
+			}
+			if tc.wantRule != "" {
+				checkChange(t, res, tc.wantRule, "")
+			}
+		})
+	}
+}
+
+func TestProfileText(t *testing.T) {
+	for _, p := range []Profile{Lenient, Go1Compat, Strict} {
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Profile
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+		if got != p {
+			t.Errorf("got %s, want %s", got, p)
+		}
+	}
+
+	var p Profile
+	if err := p.UnmarshalText([]byte("loose")); err == nil {
+		t.Error("got no error for unknown profile")
+	}
+}
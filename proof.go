@@ -0,0 +1,376 @@
+package modver
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Proof is a small client program that demonstrates a Major change.
+// See Prove.
+type Proof struct {
+	// Snippet is the Go source of the client program.
+	// It uses the older version of the changed object the way a client might.
+	Snippet string
+
+	// Proven tells whether Snippet compiles against the older version of the module
+	// and fails to compile against the newer version.
+	Proven bool
+
+	// Err is the first type-checking error in Snippet.
+	// When Proven is true,
+	// this is the error against the newer version;
+	// otherwise it is the error against the older version, if any.
+	Err string
+}
+
+// Prove tries to prove each Major finding in res
+// (which must be the result of calling Compare or CompareAll on olders and newers)
+// by building a small client program that uses the affected object,
+// and type-checking it against olders and newers.
+// It returns res with a Proof attached to the outermost Change of each Major finding for which it could build a program.
+//
+// A finding is proven if the program compiles against olders but not against newers.
+// An unproven finding may still be a real incompatibility:
+// the programs that Prove builds exercise only some of the ways that clients can use an object.
+func Prove(olders, newers []*packages.Package, res Result) Result {
+	var (
+		older    = makePackageMap(olders)
+		olderImp = makeImporter(olders)
+		newerImp = makeImporter(newers)
+	)
+
+	prove := func(r Result) Result {
+		w, ok := r.(wrapped)
+		if !ok || w.Code() != Major {
+			return r
+		}
+		snippet, ok := proofSnippet(older[w.change.PkgPath], w.change.PkgPath, w.change.Object)
+		if !ok {
+			return r
+		}
+		proof := &Proof{Snippet: snippet}
+		if err := typeCheckSnippet(snippet, olderImp); err != nil {
+			proof.Err = err.Error()
+		} else if err := typeCheckSnippet(snippet, newerImp); err != nil {
+			proof.Proven, proof.Err = true, err.Error()
+		}
+		w.change.Proof = proof
+		return w
+	}
+
+	if rs, ok := res.(Results); ok {
+		result := make(Results, 0, len(rs))
+		for _, r := range rs {
+			result = append(result, prove(r))
+		}
+		return result
+	}
+	return prove(res)
+}
+
+// proofSnippet builds a client program that uses the top-level object id in pkg,
+// or the package itself if id is empty.
+// It reports false if it cannot build one,
+// e.g. for generic objects,
+// or for objects that only code inside the module can use,
+// such as those of internal packages.
+func proofSnippet(pkg *packages.Package, pkgPath, id string) (string, bool) {
+	if pkg == nil {
+		return "", false
+	}
+
+	pw := newProofWriter(pkg.Types)
+
+	if id == "" {
+		// The package was removed.
+		pw.imports[pkgPath] = "_"
+		return pw.source(), true
+	}
+
+	obj := makeTopObjs(pkg)[id]
+	if obj == nil || !pw.writeObject(id, obj) {
+		return "", false
+	}
+
+	// The program is a client outside the module,
+	// which may not import internal packages
+	// (https://go.dev/doc/go1.4#internalpackages).
+	for path := range pw.imports {
+		if !isPublic(path) {
+			return "", false
+		}
+	}
+
+	return pw.source(), true
+}
+
+type proofWriter struct {
+	pkg     *types.Package
+	imports map[string]string // package path -> local name
+	names   map[string]bool   // local names in use
+	body    *bytes.Buffer
+}
+
+func newProofWriter(pkg *types.Package) *proofWriter {
+	return &proofWriter{
+		pkg:     pkg,
+		imports: make(map[string]string),
+		names:   map[string]bool{"proof": true, "proofImpl": true},
+		body:    new(bytes.Buffer),
+	}
+}
+
+// qualifier gives the local name for importing pkg,
+// adding it to the imports if needed.
+func (pw *proofWriter) qualifier(pkg *types.Package) string {
+	if name, ok := pw.imports[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for n := 2; pw.names[name]; n++ {
+		name = pkg.Name() + strconv.Itoa(n)
+	}
+	pw.imports[pkg.Path()], pw.names[name] = name, true
+	return name
+}
+
+func (pw *proofWriter) typeString(typ types.Type) string {
+	return types.TypeString(typ, pw.qualifier)
+}
+
+func (pw *proofWriter) printf(format string, args ...any) {
+	fmt.Fprintf(pw.body, format, args...)
+}
+
+// writeObject writes code that uses obj,
+// whose name in its package is id,
+// the way a client might.
+func (pw *proofWriter) writeObject(id string, obj types.Object) bool {
+	p := pw.qualifier(pw.pkg)
+
+	switch obj := obj.(type) {
+	case *types.Func:
+		sig := obj.Type().(*types.Signature)
+		if sig.TypeParams().Len() > 0 {
+			return false
+		}
+		recv := sig.Recv()
+		if recv == nil {
+			// Function-value assignment.
+			pw.printf("var _ %s = %s.%s\n", pw.typeString(unnamedSignature(nil, sig)), p, obj.Name())
+			return true
+		}
+		recvType := recv.Type()
+		ptr, isPtr := recvType.(*types.Pointer)
+		if isPtr {
+			recvType = ptr.Elem()
+		}
+		named, ok := recvType.(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			return false
+		}
+		// Method-expression assignment.
+		expr := fmt.Sprintf("%s.%s", p, named.Obj().Name())
+		if isPtr {
+			expr = "(*" + expr + ")"
+		}
+		pw.printf("var _ %s = %s.%s\n", pw.typeString(unnamedSignature(recv.Type(), sig)), expr, obj.Name())
+		return true
+
+	case *types.Var:
+		typ := pw.typeString(obj.Type())
+		pw.printf("var _ %s = %s.%s\n", typ, p, id)
+		pw.printf("\nfunc _() {\n\t%s.%s = *new(%s)\n}\n", p, id, typ)
+		return true
+
+	case *types.Const:
+		typ := obj.Type()
+		if b, ok := typ.(*types.Basic); ok && b.Info()&types.IsUntyped != 0 {
+			typ = types.Default(typ)
+		}
+		pw.printf("const _ %s = %s.%s\n", pw.typeString(typ), p, id)
+		return true
+
+	case *types.TypeName:
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			return false
+		}
+		if alias, ok := obj.Type().(*types.Alias); ok && alias.TypeParams().Len() > 0 {
+			return false
+		}
+		return pw.writeType(p+"."+id, obj.Type())
+	}
+
+	return false
+}
+
+// writeType writes code that uses the named type (or alias) expressed as expr.
+func (pw *proofWriter) writeType(expr string, typ types.Type) bool {
+	switch u := typ.Underlying().(type) {
+	case *types.Struct:
+		// Composite literal, plus a read of each field.
+		var fields []*types.Var
+		for i := 0; i < u.NumFields(); i++ {
+			if f := u.Field(i); f.Exported() {
+				fields = append(fields, f)
+			}
+		}
+		pw.printf("var _ = %s{", expr)
+		for i, f := range fields {
+			if i > 0 {
+				pw.printf(", ")
+			}
+			pw.printf("%s: *new(%s)", f.Name(), pw.typeString(f.Type()))
+		}
+		pw.printf("}\n")
+		pw.printf("\nfunc _(x %s) {\n", expr)
+		for _, f := range fields {
+			pw.printf("\tvar _ %s = x.%s\n", pw.typeString(f.Type()), f.Name())
+		}
+		pw.printf("}\n")
+		return true
+
+	case *types.Interface:
+		if !u.IsMethodSet() {
+			// A constraint.
+			return false
+		}
+
+		// A method value of each method.
+		pw.printf("func _(x %s) {\n", expr)
+		for i := 0; i < u.NumMethods(); i++ {
+			m := u.Method(i)
+			if !m.Exported() {
+				continue
+			}
+			sig := m.Type().(*types.Signature)
+			pw.printf("\tvar _ %s = x.%s\n", pw.typeString(unnamedSignature(nil, sig)), m.Name())
+		}
+		pw.printf("}\n")
+
+		if anyUnexportedMethods(u) {
+			// Clients cannot implement this interface.
+			return true
+		}
+
+		// An implementation of the interface.
+		pw.printf("\ntype proofImpl struct{}\n\n")
+		for i := 0; i < u.NumMethods(); i++ {
+			m := u.Method(i)
+			sig := unnamedSignature(nil, m.Type().(*types.Signature))
+			pw.printf("func (proofImpl) %s%s { panic(0) }\n\n", m.Name(), strings.TrimPrefix(pw.typeString(sig), "func"))
+		}
+		pw.printf("var _ %s = proofImpl{}\n", expr)
+		return true
+
+	case *types.Basic:
+		// Conversion to and from the underlying type.
+		under := pw.typeString(u)
+		pw.printf("func _(x %s) {\n\t_ = %s(x)\n\tx = %s(*new(%s))\n}\n", expr, under, expr, under)
+		return true
+
+	default:
+		// Assignment to and from the underlying type.
+		under := pw.typeString(u)
+		pw.printf("func _(x %s) {\n\tvar _ %s = x\n\tx = *new(%s)\n}\n", expr, under, under)
+		return true
+	}
+}
+
+func (pw *proofWriter) source() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "package proof")
+	if len(pw.imports) > 0 {
+		fmt.Fprintln(buf, "\nimport (")
+		for _, path := range sortedKeys(pw.imports) {
+			fmt.Fprintf(buf, "\t%s %q\n", pw.imports[path], path)
+		}
+		fmt.Fprintln(buf, ")")
+	}
+	if pw.body.Len() > 0 {
+		fmt.Fprintln(buf)
+		buf.Write(pw.body.Bytes())
+	}
+
+	if formatted, err := format.Source(buf.Bytes()); err == nil {
+		return string(formatted)
+	}
+	return buf.String()
+}
+
+// unnamedSignature is sig as a non-generic function type with unnamed parameters and results,
+// with recv (if non-nil) prepended to the parameters
+// (as in the type of a method expression).
+func unnamedSignature(recv types.Type, sig *types.Signature) *types.Signature {
+	var params []*types.Var
+	if recv != nil {
+		params = append(params, types.NewParam(token.NoPos, nil, "", recv))
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		params = append(params, types.NewParam(token.NoPos, nil, "", sig.Params().At(i).Type()))
+	}
+	var results []*types.Var
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, types.NewParam(token.NoPos, nil, "", sig.Results().At(i).Type()))
+	}
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), types.NewTuple(results...), sig.Variadic())
+}
+
+// importer resolves imports from a set of already-loaded packages and their dependencies.
+type importer map[string]*types.Package
+
+func makeImporter(pkgs []*packages.Package) importer {
+	imp := make(importer)
+
+	var add func(*types.Package)
+	add = func(pkg *types.Package) {
+		if _, ok := imp[pkg.Path()]; ok {
+			return
+		}
+		imp[pkg.Path()] = pkg
+		for _, dep := range pkg.Imports() {
+			add(dep)
+		}
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.Types != nil {
+			add(pkg.Types)
+		}
+	}
+	return imp
+}
+
+// Import implements types.Importer.
+func (imp importer) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg, ok := imp[path]; ok {
+		return pkg, nil
+	}
+	return nil, fmt.Errorf("package %s not found", path)
+}
+
+// typeCheckSnippet parses and type-checks the Go source in snippet,
+// resolving imports with imp.
+// It returns the first error found, if any.
+func typeCheckSnippet(snippet string, imp importer) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "proof.go", snippet, 0)
+	if err != nil {
+		return err
+	}
+	conf := types.Config{Importer: imp}
+	_, err = conf.Check("proof", fset, []*ast.File{file}, nil)
+	return err
+}
@@ -0,0 +1,71 @@
+package modver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProve(t *testing.T) {
+	cases := []struct {
+		tree, name  string
+		wantProof   bool
+		wantProven  bool
+		wantSnippet string
+	}{{
+		tree: "major", name: "addparam", wantProof: true, wantProven: true, wantSnippet: "var _ func() = addparam.F",
+	}, {
+		tree: "major", name: "rmfield", wantProof: true, wantProven: true, wantSnippet: "var _ = rmfield.A{X: *new(int), Y: *new(int)}",
+	}, {
+		tree: "major", name: "addmethod", wantProof: true, wantProven: true, wantSnippet: "var _ addmethod.X = proofImpl{}",
+	}, {
+		tree: "major", name: "rmmethod", wantProof: true, wantProven: true, wantSnippet: "var _ func(rmmethod.X) = rmmethod.X.M2",
+	}, {
+		tree: "major", name: "rmpackage", wantProof: true, wantProven: true, wantSnippet: `_ "rmpackage/subpkg"`,
+	}, {
+		tree: "major", name: "chtag", wantProof: true, wantProven: false,
+	}, {
+		tree: "major", name: "reachableinternal", wantProof: false,
+	}, {
+		tree: "minor", name: "addfield", wantProof: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.tree+"/"+tc.name, func(t *testing.T) {
+			err := withTestDirs(filepath.Join("testdata", tc.tree), tc.name, func(olderTestDir, newerTestDir string) {
+				olders, newers, err := LoadDirs(olderTestDir, newerTestDir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				res := Prove(olders, newers, Compare(olders, newers))
+
+				changes := res.Changes()
+				if len(changes) == 0 {
+					t.Fatal("no changes")
+				}
+				proof := changes[0].Proof
+				if !tc.wantProof {
+					if proof != nil {
+						t.Errorf("got proof %+v, want none", proof)
+					}
+					return
+				}
+				if proof == nil {
+					t.Fatal("got no proof")
+				}
+				if proof.Proven != tc.wantProven {
+					t.Errorf("got proven %v, want %v (error: %s)", proof.Proven, tc.wantProven, proof.Err)
+				}
+				if !strings.Contains(proof.Snippet, tc.wantSnippet) {
+					t.Errorf("snippet does not contain %q:\n%s", tc.wantSnippet, proof.Snippet)
+				}
+				if tc.wantProven && proof.Err == "" {
+					t.Error("proven, but with no error against newer")
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
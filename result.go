@@ -11,6 +11,13 @@ type Result interface {
 	Code() ResultCode
 	String() string
 
+	// Changes returns the chain of reasons for this Result
+	// as structured Change records,
+	// outermost first.
+	// A bare ResultCode has no Changes.
+	// The Changes of a Results value are the concatenation of its members' Changes.
+	Changes() []Change
+
 	sub(code ResultCode) Result
 }
 
@@ -29,6 +36,9 @@ const (
 func (r ResultCode) Code() ResultCode           { return r }
 func (r ResultCode) sub(code ResultCode) Result { return code }
 
+// Changes implements Result.Changes.
+func (r ResultCode) Changes() []Change { return nil }
+
 // String implements Result.String.
 func (r ResultCode) String() string {
 	switch r {
@@ -69,8 +79,65 @@ func (r *ResultCode) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Results is a Result made up of zero or more other Results,
+// as produced by CompareAll.
+// Its Code is the highest Code among its members,
+// or None if it has no members.
+type Results []Result
+
+// Code implements Result.Code.
+func (rs Results) Code() ResultCode {
+	code := None
+	for _, r := range rs {
+		if c := r.Code(); c > code {
+			code = c
+		}
+	}
+	return code
+}
+
+func (rs Results) sub(code ResultCode) Result {
+	result := make(Results, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, r.sub(code))
+	}
+	return result
+}
+
+// String implements Result.String.
+func (rs Results) String() string {
+	if len(rs) == 0 {
+		return None.String()
+	}
+	strs := make([]string, 0, len(rs))
+	for _, r := range rs {
+		strs = append(strs, r.String())
+	}
+	return strings.Join(strs, "; ")
+}
+
+// Changes implements Result.Changes.
+func (rs Results) Changes() []Change {
+	var result []Change
+	for _, r := range rs {
+		result = append(result, r.Changes()...)
+	}
+	return result
+}
+
+func (rs Results) pretty(out io.Writer, level int, decls bool) {
+	if len(rs) == 0 {
+		prettyLevel(out, None, level, decls)
+		return
+	}
+	for _, r := range rs {
+		prettyLevel(out, r, level, decls)
+	}
+}
+
 type wrapped struct {
 	r       Result
+	change  Change
 	whyfmt  string
 	whyargs []any
 }
@@ -83,6 +150,27 @@ func (w wrapped) sub(code ResultCode) Result {
 	return result
 }
 
+// Changes implements Result.Changes.
+// Changes deeper in the chain inherit the package path and object name of this one
+// when they don't have their own.
+func (w wrapped) Changes() []Change {
+	ch := w.change
+	ch.Code = w.Code()
+	ch.Reason = w.why()
+
+	result := []Change{ch}
+	for _, inner := range w.r.Changes() {
+		if inner.PkgPath == "" {
+			inner.PkgPath = ch.PkgPath
+		}
+		if inner.Object == "" {
+			inner.Object = ch.Object
+		}
+		result = append(result, inner)
+	}
+	return result
+}
+
 func (w wrapped) why() string {
 	return fmt.Sprintf(w.whyfmt, w.whyargs...)
 }
@@ -92,9 +180,37 @@ func (w wrapped) String() string {
 	return fmt.Sprintf("%s: %s", w.r, w.why())
 }
 
-func (w wrapped) pretty(out io.Writer, level int) {
-	fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", level), w.why())
-	prettyLevel(out, w.r, level+1)
+func (w wrapped) pretty(out io.Writer, level int, decls bool) {
+	indent := strings.Repeat("  ", level)
+	if w.change.Rule != "" {
+		fmt.Fprintf(out, "%s%s [%s]\n", indent, w.why(), w.change.Rule)
+	} else {
+		fmt.Fprintf(out, "%s%s\n", indent, w.why())
+	}
+	if decls {
+		prettyDecl(out, level+1, "older", w.change.OldDecl)
+		prettyDecl(out, level+1, "newer", w.change.NewDecl)
+		if proof := w.change.Proof; proof != nil {
+			if proof.Proven {
+				prettyDecl(out, level+1, "proven by this client program, which compiles against older but not newer", proof.Snippet)
+				prettyDecl(out, level+1, "error against newer", proof.Err)
+			} else {
+				prettyDecl(out, level+1, "unproven with this client program", proof.Snippet)
+			}
+		}
+	}
+	prettyLevel(out, w.r, level+1, decls)
+}
+
+func prettyDecl(out io.Writer, level int, label, decl string) {
+	if decl == "" {
+		return
+	}
+	indent := strings.Repeat("  ", level)
+	fmt.Fprintf(out, "%s%s:\n", indent, label)
+	for _, line := range strings.Split(strings.TrimRight(decl, "\n"), "\n") {
+		fmt.Fprintf(out, "%s    %s\n", indent, line)
+	}
 }
 
 func rwrap(r Result, s string) Result {
@@ -109,17 +225,27 @@ func rwrapf(r Result, format string, args ...any) Result {
 }
 
 type prettyer interface {
-	pretty(io.Writer, int)
+	pretty(out io.Writer, level int, decls bool)
 }
 
 // Pretty writes a pretty representation of res to out.
 func Pretty(out io.Writer, res Result) {
-	prettyLevel(out, res, 0)
+	prettyLevel(out, res, 0, false)
+}
+
+// PrettyDecls is like Pretty,
+// but also writes the older and newer declarations of each top-level object that changed
+// (see Change.OldDecl and Change.NewDecl),
+// and the client program that proves or fails to prove each Major change,
+// if any
+// (see Prove).
+func PrettyDecls(out io.Writer, res Result) {
+	prettyLevel(out, res, 0, true)
 }
 
-func prettyLevel(out io.Writer, res Result, level int) {
+func prettyLevel(out io.Writer, res Result, level int, decls bool) {
 	if p, ok := res.(prettyer); ok {
-		p.pretty(out, level)
+		p.pretty(out, level, decls)
 	} else {
 		fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", level), res)
 	}
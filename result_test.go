@@ -24,6 +24,29 @@ func TestPretty(t *testing.T) {
 	}
 }
 
+func TestResults(t *testing.T) {
+	var rs Results
+	if rs.Code() != None {
+		t.Errorf("got %s, want None", rs.Code())
+	}
+
+	rs = Results{rwrap(Minor, "foo"), rwrap(Major, "bar"), rwrap(Patchlevel, "baz")}
+	if rs.Code() != Major {
+		t.Errorf("got %s, want Major", rs.Code())
+	}
+
+	buf := new(bytes.Buffer)
+	Pretty(buf, rs)
+	const want = "foo\n  Minor\nbar\n  Major\nbaz\n  Patchlevel\n"
+	if buf.String() != want {
+		t.Errorf("got %s, want %s", buf, want)
+	}
+
+	if got := rs.sub(Patchlevel).Code(); got != Patchlevel {
+		t.Errorf("after sub, got %s, want Patchlevel", got)
+	}
+}
+
 func TestMarshalResultCode(t *testing.T) {
 	cases := []struct {
 		rc      ResultCode
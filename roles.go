@@ -0,0 +1,286 @@
+package modver
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// InterfaceRole tells whether clients of a module may implement one of its interface types.
+// Adding a method to an interface breaks clients that implement it,
+// but not clients that only use values of it that the module supplies.
+// See WithInterfaceRole.
+type InterfaceRole int
+
+// Values for InterfaceRole.
+const (
+	// Implementable is the role of an interface that clients may implement.
+	// It appears where clients supply values to the module,
+	// such as in the parameters of exported functions,
+	// in exported struct fields and variables,
+	// and in type constraints;
+	// or the module checks for it with a type assertion,
+	// so clients may implement it to opt in to some behavior.
+	// This is the role of any interface that does not appear in the public API at all.
+	Implementable InterfaceRole = iota
+
+	// ConsumerOnly is the role of an interface that clients only consume:
+	// it appears only where the module supplies values to clients,
+	// such as in the results of exported functions.
+	ConsumerOnly
+)
+
+// String returns the name of r.
+func (r InterfaceRole) String() string {
+	switch r {
+	case Implementable:
+		return "implementable"
+	case ConsumerOnly:
+		return "consumeronly"
+	default:
+		return fmt.Sprintf("InterfaceRole(%d)", int(r))
+	}
+}
+
+func (r InterfaceRole) MarshalText() ([]byte, error) {
+	switch r {
+	case Implementable, ConsumerOnly:
+		return []byte(r.String()), nil
+	}
+	return nil, fmt.Errorf("unknown InterfaceRole value %d", r)
+}
+
+func (r *InterfaceRole) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "implementable":
+		*r = Implementable
+	case "consumeronly":
+		*r = ConsumerOnly
+	default:
+		return fmt.Errorf("unknown InterfaceRole value %q", text)
+	}
+	return nil
+}
+
+// interfaceRoles determines the roles of the named interface types in the module made up of pkgs,
+// keyed by package path,
+// from where they appear in the exported declarations of its public packages
+// and in type assertions anywhere in the module.
+// Interfaces that do not appear in the result are Implementable.
+func interfaceRoles(pkgs map[string]*packages.Package) map[typeKey]InterfaceRole {
+	w := &roleWalker{
+		pkgs:  pkgs,
+		roles: make(map[typeKey]InterfaceRole),
+		seen:  make(map[roleVisit]bool),
+	}
+
+	for _, pkgPath := range sortedKeys(pkgs) {
+		if !isPublic(pkgPath) {
+			continue
+		}
+		for id, obj := range makeTopObjs(pkgs[pkgPath]) {
+			if obj == nil || !isExported(id) || isMethodOfUnexportedType(obj) {
+				continue
+			}
+			switch obj := obj.(type) {
+			case *types.Var:
+				// Clients can both read and assign exported variables.
+				w.walk(obj.Type(), false)
+				w.walk(obj.Type(), true)
+
+			case *types.Func:
+				w.walk(obj.Type(), false)
+
+			case *types.TypeName:
+				w.walkTypeParams(typeParams(obj.Type()))
+				if !types.IsInterface(obj.Type()) {
+					// An interface type is walked where it appears,
+					// which tells who supplies its values.
+					w.walk(obj.Type(), false)
+				}
+			}
+		}
+	}
+
+	for _, pkgPath := range sortedKeys(pkgs) {
+		pkg := pkgs[pkgPath]
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				var exprs []ast.Expr
+				switch n := n.(type) {
+				case *ast.TypeAssertExpr:
+					exprs = append(exprs, n.Type)
+				case *ast.TypeSwitchStmt:
+					for _, stmt := range n.Body.List {
+						exprs = append(exprs, stmt.(*ast.CaseClause).List...)
+					}
+				}
+				for _, expr := range exprs {
+					if expr == nil {
+						continue
+					}
+					if named, ok := types.Unalias(pkg.TypesInfo.TypeOf(expr)).(*types.Named); ok && types.IsInterface(named) {
+						if key, ok := w.key(named); ok {
+							w.roles[key] = Implementable
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	return w.roles
+}
+
+// roleWalker walks the types in the exported declarations of a module,
+// noting for each named interface type in the module
+// whether clients supply values of it to the module
+// or only receive values of it from the module.
+type roleWalker struct {
+	pkgs  map[string]*packages.Package
+	roles map[typeKey]InterfaceRole
+	seen  map[roleVisit]bool
+}
+
+type roleVisit struct {
+	key typeKey
+	in  bool
+}
+
+// walk walks typ,
+// whose values clients supply to the module if in is true,
+// and otherwise receive from it.
+func (w *roleWalker) walk(typ types.Type, in bool) {
+	switch typ := typ.(type) {
+	case *types.Named:
+		for i := 0; i < typ.TypeArgs().Len(); i++ {
+			w.walk(typ.TypeArgs().At(i), false)
+			w.walk(typ.TypeArgs().At(i), true)
+		}
+		key, ok := w.key(typ)
+		if !ok {
+			return
+		}
+		if w.seen[roleVisit{key: key, in: in}] {
+			return
+		}
+		w.seen[roleVisit{key: key, in: in}] = true
+
+		if types.IsInterface(typ) {
+			if in {
+				w.roles[key] = Implementable
+			} else if _, ok := w.roles[key]; !ok {
+				w.roles[key] = ConsumerOnly
+			}
+			w.walk(typ.Underlying(), in)
+			return
+		}
+		w.walk(typ.Underlying(), in)
+		for i := 0; i < typ.NumMethods(); i++ {
+			if m := typ.Method(i); m.Exported() {
+				w.walk(m.Type(), false)
+			}
+		}
+
+	case *types.Alias:
+		w.walk(types.Unalias(typ), in)
+
+	case *types.Pointer:
+		w.walk(typ.Elem(), in)
+	case *types.Slice:
+		w.walk(typ.Elem(), in)
+	case *types.Array:
+		w.walk(typ.Elem(), in)
+	case *types.Map:
+		w.walk(typ.Key(), in)
+		w.walk(typ.Elem(), in)
+
+	case *types.Chan:
+		switch typ.Dir() {
+		case types.RecvOnly:
+			w.walk(typ.Elem(), in)
+		case types.SendOnly:
+			w.walk(typ.Elem(), !in)
+		default:
+			w.walk(typ.Elem(), false)
+			w.walk(typ.Elem(), true)
+		}
+
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if f := typ.Field(i); f.Exported() || f.Embedded() {
+				// Clients can assign to the fields of a struct they receive,
+				// as well as read them.
+				w.walk(f.Type(), true)
+				if !in {
+					w.walk(f.Type(), false)
+				}
+			}
+		}
+
+	case *types.Interface:
+		for i := 0; i < typ.NumExplicitMethods(); i++ {
+			if m := typ.ExplicitMethod(i); m.Exported() {
+				w.walk(m.Type(), in)
+			}
+		}
+		for i := 0; i < typ.NumEmbeddeds(); i++ {
+			w.walk(typ.EmbeddedType(i), in)
+		}
+
+	case *types.Signature:
+		// The parameters of a function that the module supplies are supplied by clients,
+		// and vice versa.
+		w.walkTypeParams(typ.TypeParams())
+		w.walk(typ.Params(), !in)
+		w.walk(typ.Results(), in)
+
+	case *types.Tuple:
+		for i := 0; i < typ.Len(); i++ {
+			w.walk(typ.At(i).Type(), in)
+		}
+
+	case *types.Union:
+		for i := 0; i < typ.Len(); i++ {
+			w.walk(typ.Term(i).Type(), in)
+		}
+	}
+}
+
+// walkTypeParams walks the constraints of tparams.
+// Clients supply the type arguments that must satisfy them.
+func (w *roleWalker) walkTypeParams(tparams *types.TypeParamList) {
+	for i := 0; i < tparams.Len(); i++ {
+		w.walk(tparams.At(i).Constraint(), true)
+	}
+}
+
+// key gives the key of named,
+// and false if it is not in the module.
+func (w *roleWalker) key(named *types.Named) (typeKey, bool) {
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil || w.pkgs[obj.Pkg().Path()] == nil {
+		return typeKey{}, false
+	}
+	return typeKey{pkgPath: obj.Pkg().Path(), name: obj.Name()}, true
+}
+
+// interfaceRole gives the role of named,
+// a named interface type in the older version of the module.
+func (c *comparer) interfaceRole(named *types.Named) InterfaceRole {
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil {
+		return Implementable
+	}
+	key := typeKey{pkgPath: obj.Pkg().Path(), name: obj.Name()}
+	if role, ok := c.interfaceRoleOverrides[key]; ok {
+		return role
+	}
+	if role, ok := c.olderRoles[key]; ok {
+		return role
+	}
+	return Implementable
+}
@@ -0,0 +1,72 @@
+package modver
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestInterfaceRoles(t *testing.T) {
+	compareCase(t, "none", "roles", func(t *testing.T, olders, _ []*packages.Package) {
+		roles := interfaceRoles(makePackageMap(olders))
+
+		want := map[string]InterfaceRole{
+			"Returned":   ConsumerOnly,
+			"Param":      Implementable,
+			"Field":      Implementable,
+			"Var":        Implementable,
+			"Callback":   ConsumerOnly,
+			"Handler":    Implementable,
+			"Asserted":   Implementable,
+			"Constraint": Implementable,
+			"Embedded":   ConsumerOnly,
+			"Outer":      ConsumerOnly,
+		}
+		for name, wantRole := range want {
+			if got := roles[typeKey{pkgPath: "roles", name: name}]; got != wantRole {
+				t.Errorf("%s: got %s, want %s", name, got, wantRole)
+			}
+		}
+		if role, ok := roles[typeKey{pkgPath: "roles", name: "Unused"}]; ok {
+			t.Errorf("Unused: got %s, want no role", role)
+		}
+		if got := roles[typeKey{pkgPath: "roles/internal/impl", name: "Result"}]; got != ConsumerOnly {
+			t.Errorf("impl.Result: got %s, want %s", got, ConsumerOnly)
+		}
+	})
+}
+
+func TestInterfaceRoleOptions(t *testing.T) {
+	// The levels of these cases without options are checked by TestCompare.
+	cases := []struct {
+		dir, name string
+		opts      []Option
+		want      ResultCode
+		wantRule  string
+	}{{
+		dir: "minor", name: "consumerintf",
+		opts:     []Option{WithInterfaceRole("consumerintf", "Iter", Implementable)},
+		want:     Major,
+		wantRule: "MV110",
+	}, {
+		dir: "minor", name: "consumerintf",
+		opts:     []Option{WithProfile(Go1Compat)},
+		want:     Major,
+		wantRule: "MV131",
+	}, {
+		dir: "major", name: "fieldintf",
+		opts:     []Option{WithInterfaceRole("fieldintf", "Logger", ConsumerOnly)},
+		want:     Minor,
+		wantRule: "MV217",
+	}}
+
+	for _, tc := range cases {
+		compareCase(t, tc.dir, tc.name, func(t *testing.T, olders, newers []*packages.Package) {
+			res := CompareAll(olders, newers, tc.opts...)
+			if got := res.Code(); got != tc.want {
+				t.Errorf("with %d option(s): got %s, want %s", len(tc.opts), got, tc.want)
+			}
+			checkChange(t, res, tc.wantRule, "")
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package modver
+
+import (
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Summary is a per-package breakdown of a comparison,
+// keyed by package path.
+// See Summarize.
+type Summary map[string]*PackageSummary
+
+// PackageSummary summarizes the changes to one public package.
+type PackageSummary struct {
+	// Result is the changes found in the package,
+	// and Result.Code() is the highest level among them.
+	// This includes changes to unexported objects.
+	Result Results
+
+	// Added and Removed are the exported top-level objects added to and removed from the package,
+	// sorted.
+	// If the whole package was added or removed,
+	// these list all of its exported objects.
+	Added, Removed []string
+
+	// Changed lists the exported top-level objects
+	// present in both versions of the package
+	// that changed,
+	// keyed by the level of the change.
+	// Each list is sorted.
+	Changed map[ResultCode][]string
+}
+
+// Code is the highest level of change in the package.
+func (ps *PackageSummary) Code() ResultCode {
+	return ps.Result.Code()
+}
+
+// Summarize groups the changes in res by package.
+// The value of res must be the result of calling CompareAll
+// (and optionally Prove)
+// on olders and newers.
+//
+// The Summary has an entry for every public package in olders or newers,
+// including those with no changes.
+// Changes that apply to the whole module
+// (such as raising the minimum Go version)
+// are in an entry keyed by the empty string.
+func Summarize(olders, newers []*packages.Package, res Result) Summary {
+	var (
+		older = makePackageMap(olders)
+		newer = makePackageMap(newers)
+
+		olderObjs = ExportedObjects(olders)
+		newerObjs = ExportedObjects(newers)
+	)
+
+	summary := make(Summary)
+	for _, pkgPath := range sortedKeys(older, newer) {
+		if isPublic(pkgPath) {
+			summary[pkgPath] = &PackageSummary{Changed: make(map[ResultCode][]string)}
+		}
+	}
+
+	rs, ok := res.(Results)
+	if !ok {
+		rs = Results{res}
+	}
+
+	for _, r := range rs {
+		changes := r.Changes()
+		if len(changes) == 0 {
+			continue
+		}
+		ch := changes[0]
+
+		ps := summary[ch.PkgPath]
+		if ps == nil {
+			if ch.PkgPath != "" {
+				// Not a public package.
+				continue
+			}
+			ps = &PackageSummary{Changed: make(map[ResultCode][]string)}
+			summary[""] = ps
+		}
+		ps.Result = append(ps.Result, r)
+
+		switch {
+		case ch.Object == "" && ch.Kind == PackageAdded:
+			ps.Added = append(ps.Added, newerObjs[ch.PkgPath]...)
+
+		case ch.Object == "" && (ch.Kind == PackageRemoved || ch.Kind == RemovedWithoutDeprecation):
+			ps.Removed = append(ps.Removed, olderObjs[ch.PkgPath]...)
+
+		case ch.Object == "":
+			// Some other change to the package or module as a whole.
+
+		case ch.Kind == ObjectAdded || ch.Kind == MethodAdded:
+			if slices.Contains(newerObjs[ch.PkgPath], ch.Object) {
+				ps.Added = append(ps.Added, ch.Object)
+			}
+
+		case ch.Kind == ObjectRemoved || ch.Kind == RemovedWithoutDeprecation:
+			if slices.Contains(olderObjs[ch.PkgPath], ch.Object) {
+				ps.Removed = append(ps.Removed, ch.Object)
+			}
+
+		default:
+			if slices.Contains(olderObjs[ch.PkgPath], ch.Object) {
+				ps.Changed[r.Code()] = append(ps.Changed[r.Code()], ch.Object)
+			}
+		}
+	}
+
+	for _, ps := range summary {
+		slices.Sort(ps.Added)
+		slices.Sort(ps.Removed)
+		for _, ids := range ps.Changed {
+			slices.Sort(ids)
+		}
+	}
+
+	return summary
+}
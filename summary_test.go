@@ -0,0 +1,66 @@
+package modver
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "multiple", func(olderTestDir, newerTestDir string) {
+		olders, newers, err := LoadDirs(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		summary := Summarize(olders, newers, CompareAll(olders, newers))
+
+		if len(summary) != 1 {
+			t.Fatalf("got %d packages, want 1", len(summary))
+		}
+		ps := summary["multiple"]
+		if ps == nil {
+			t.Fatal("no summary for package multiple")
+		}
+		if ps.Code() != Major {
+			t.Errorf("got code %s, want Major", ps.Code())
+		}
+		if len(ps.Result) != 4 {
+			t.Errorf("got %d results, want 4", len(ps.Result))
+		}
+		if !reflect.DeepEqual(ps.Added, []string{"G"}) {
+			t.Errorf("got added %v, want [G]", ps.Added)
+		}
+		if len(ps.Removed) != 0 {
+			t.Errorf("got removed %v, want none", ps.Removed)
+		}
+		wantChanged := map[ResultCode][]string{Major: {"F"}, Minor: {"X"}}
+		if !reflect.DeepEqual(ps.Changed, wantChanged) {
+			t.Errorf("got changed %v, want %v", ps.Changed, wantChanged)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = withTestDirs(filepath.Join("testdata", "major"), "rmpackage", func(olderTestDir, newerTestDir string) {
+		olders, newers, err := LoadDirs(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		summary := Summarize(olders, newers, CompareAll(olders, newers))
+
+		ps := summary["rmpackage/subpkg"]
+		if ps == nil {
+			t.Fatal("no summary for package rmpackage/subpkg")
+		}
+		if ps.Code() != Major || len(ps.Removed) == 0 {
+			t.Errorf("got code %s and removed %v, want Major and some removed objects", ps.Code(), ps.Removed)
+		}
+		if ps := summary["rmpackage"]; ps == nil || ps.Code() != None {
+			t.Errorf("got %v for unchanged package rmpackage, want None", ps)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
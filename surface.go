@@ -0,0 +1,154 @@
+package modver
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// surface is the set of named types in a module
+// that clients can reach from the exported declarations of its public packages,
+// including unexported types and types in internal packages.
+// Clients can call the exported methods
+// and use the exported fields
+// of such types,
+// even though they cannot name them.
+type surface map[typeKey]bool
+
+type typeKey struct {
+	pkgPath, name string
+}
+
+// publicSurface computes the surface of the module made up of pkgs,
+// keyed by package path.
+func publicSurface(pkgs map[string]*packages.Package) surface {
+	s := make(surface)
+
+	var walk func(types.Type)
+	walk = func(typ types.Type) {
+		switch typ := typ.(type) {
+		case *types.Named:
+			for i := 0; i < typ.TypeArgs().Len(); i++ {
+				walk(typ.TypeArgs().At(i))
+			}
+			obj := typ.Obj()
+			if obj.Pkg() == nil || pkgs[obj.Pkg().Path()] == nil {
+				// Not in this module.
+				return
+			}
+			key := typeKey{pkgPath: obj.Pkg().Path(), name: obj.Name()}
+			if s[key] {
+				return
+			}
+			s[key] = true
+			walk(typ.Underlying())
+			for i := 0; i < typ.NumMethods(); i++ {
+				if m := typ.Method(i); m.Exported() {
+					walk(m.Type())
+				}
+			}
+
+		case *types.Alias:
+			walk(types.Unalias(typ))
+
+		case *types.Pointer:
+			walk(typ.Elem())
+		case *types.Slice:
+			walk(typ.Elem())
+		case *types.Array:
+			walk(typ.Elem())
+		case *types.Chan:
+			walk(typ.Elem())
+		case *types.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+
+		case *types.Struct:
+			for i := 0; i < typ.NumFields(); i++ {
+				// The members of embedded fields are promoted,
+				// even when the fields themselves are unexported.
+				if f := typ.Field(i); f.Exported() || f.Embedded() {
+					walk(f.Type())
+				}
+			}
+
+		case *types.Interface:
+			for i := 0; i < typ.NumExplicitMethods(); i++ {
+				if m := typ.ExplicitMethod(i); m.Exported() {
+					walk(m.Type())
+				}
+			}
+			for i := 0; i < typ.NumEmbeddeds(); i++ {
+				walk(typ.EmbeddedType(i))
+			}
+
+		case *types.Signature:
+			walkTypeParams(typ.TypeParams(), walk)
+			walk(typ.Params())
+			walk(typ.Results())
+
+		case *types.Tuple:
+			for i := 0; i < typ.Len(); i++ {
+				walk(typ.At(i).Type())
+			}
+
+		case *types.TypeParam:
+			walk(typ.Constraint())
+
+		case *types.Union:
+			for i := 0; i < typ.Len(); i++ {
+				walk(typ.Term(i).Type())
+			}
+		}
+	}
+
+	for _, pkgPath := range sortedKeys(pkgs) {
+		if !isPublic(pkgPath) {
+			continue
+		}
+		for id, obj := range makeTopObjs(pkgs[pkgPath]) {
+			if obj == nil || !isExported(id) || isMethodOfUnexportedType(obj) {
+				continue
+			}
+			if _, ok := obj.(*types.TypeName); ok {
+				walkTypeParams(typeParams(obj.Type()), walk)
+			}
+			walk(obj.Type())
+		}
+	}
+
+	return s
+}
+
+func walkTypeParams(tparams *types.TypeParamList, walk func(types.Type)) {
+	for i := 0; i < tparams.Len(); i++ {
+		walk(tparams.At(i).Constraint())
+	}
+}
+
+// isPublicObject tells whether the top-level object obj,
+// whose name in the package at pkgPath is id,
+// is part of the public API given the surface s.
+// That is true of the exported objects of public packages,
+// except for methods of unexported types
+// (https://github.com/bobg/modver/issues/36).
+// It is also true of the exported methods of unexported types,
+// and of the types in internal packages and their exported methods,
+// that are in s.
+func isPublicObject(s surface, pkgPath, id string, obj types.Object) bool {
+	if !isExported(id) {
+		return false
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if named := recvNamed(fn); named != nil {
+			return s[typeKey{pkgPath: pkgPath, name: named.Obj().Name()}] || (isPublic(pkgPath) && named.Obj().Exported())
+		}
+	}
+	if isPublic(pkgPath) {
+		return true
+	}
+	if _, ok := obj.(*types.TypeName); ok {
+		return s[typeKey{pkgPath: pkgPath, name: id}]
+	}
+	return false
+}
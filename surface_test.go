@@ -0,0 +1,25 @@
+package modver
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPublicSurface(t *testing.T) {
+	err := withTestDirs(filepath.Join("testdata", "major"), "reachableinternal", func(olderTestDir, newerTestDir string) {
+		olders, _, err := LoadDirs(olderTestDir, newerTestDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := publicSurface(makePackageMap(olders))
+		if !s[typeKey{pkgPath: "reachableinternal/internal/store", name: "DB"}] {
+			t.Errorf("internal type DB not in surface %v", s)
+		}
+		if len(s) != 1 {
+			t.Errorf("got surface %v, want only DB", s)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,215 @@
+package modver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagComparer compares the values for one key in the older and newer struct tags of a field,
+// understanding what they mean to the code that reads them,
+// such as an encoder.
+// Without a TagComparer for a key,
+// Compare and CompareAll treat any change in its value as Major.
+// See WithTagComparer.
+type TagComparer interface {
+	// CompareTag compares older and newer,
+	// the values for the key in the older and newer tags of the struct field named field.
+	// Either may be "",
+	// meaning that the key is absent from that tag.
+	// It returns the level of the change
+	// and, if that is not None,
+	// an explanation of it.
+	CompareTag(field, older, newer string) (ResultCode, string)
+}
+
+// TagComparerFunc is a function that implements TagComparer.
+type TagComparerFunc func(field, older, newer string) (ResultCode, string)
+
+// CompareTag implements TagComparer.
+func (f TagComparerFunc) CompareTag(field, older, newer string) (ResultCode, string) {
+	return f(field, older, newer)
+}
+
+// defaultTagComparers gives the TagComparers that Compare and CompareAll use
+// unless overridden with WithTagComparer.
+func defaultTagComparers() map[string]TagComparer {
+	return map[string]TagComparer{
+		"json": nameOptsComparer{
+			encoding: "JSON",
+			major:    map[string]bool{"string": true},
+		},
+		"yaml": nameOptsComparer{
+			encoding:    "YAML",
+			defaultName: strings.ToLower,
+			major:       map[string]bool{"inline": true},
+		},
+		"xml": nameOptsComparer{
+			encoding: "XML",
+			major: map[string]bool{
+				"any":      true,
+				"attr":     true,
+				"cdata":    true,
+				"chardata": true,
+				"comment":  true,
+				"innerxml": true,
+			},
+		},
+		"protobuf":     protobufComparer{},
+		"protobuf_key": protobufComparer{},
+		"protobuf_val": protobufComparer{},
+	}
+}
+
+// nameOptsComparer is a TagComparer for tags in the style of encoding/json:
+// a name,
+// or "-" to leave the field out of the encoding,
+// followed by comma-separated options.
+// A change in the name (which defaults to one derived from the field name)
+// changes the encoding and is Major,
+// as is a change in one of the options in major.
+// A change in any other option,
+// such as omitempty,
+// changes only which values are encoded and is Patchlevel.
+// Reordering the options is no change.
+type nameOptsComparer struct {
+	encoding    string              // the name of the encoding, for explanations
+	defaultName func(string) string // derives the default name from the field name; nil means use it as is
+	major       map[string]bool
+}
+
+func (n nameOptsComparer) CompareTag(field, older, newer string) (ResultCode, string) {
+	name, opts, skip := n.parse(field, older)
+	newName, newOpts, newSkip := n.parse(field, newer)
+
+	switch {
+	case skip && newSkip:
+		return None, ""
+	case newSkip:
+		return Major, fmt.Sprintf("field is no longer encoded in %s", n.encoding)
+	case skip:
+		return Major, fmt.Sprintf("field is now encoded in %s as %q", n.encoding, newName)
+	case name != newName:
+		return Major, fmt.Sprintf("%s name changed from %q to %q", n.encoding, name, newName)
+	}
+
+	return compareTagOpts(n.encoding, opts, newOpts, n.major)
+}
+
+// parse parses val,
+// the value of a tag on the field named field,
+// into the name of the field in the encoding and the set of options,
+// and tells whether the field is left out of the encoding.
+func (n nameOptsComparer) parse(field, val string) (name string, opts map[string]bool, skip bool) {
+	if val == "-" {
+		return "", nil, true
+	}
+	name, rest, _ := strings.Cut(val, ",")
+	if name == "" {
+		name = field
+		if n.defaultName != nil {
+			name = n.defaultName(field)
+		}
+	}
+	opts = make(map[string]bool)
+	if rest != "" {
+		for _, opt := range strings.Split(rest, ",") {
+			opts[opt] = true
+		}
+	}
+	return name, opts, false
+}
+
+// compareTagOpts compares the older and newer sets of options in a tag for the named encoding.
+// Adding or removing one of the options in major is Major,
+// and adding or removing any other is Patchlevel.
+func compareTagOpts(encoding string, opts, newOpts, major map[string]bool) (ResultCode, string) {
+	var (
+		res = None
+		why string
+	)
+	for _, opt := range sortedKeys(opts, newOpts) {
+		if opts[opt] == newOpts[opt] {
+			continue
+		}
+		code := Patchlevel
+		if major[opt] {
+			code = Major
+		}
+		if code <= res {
+			continue
+		}
+		res = code
+		if opts[opt] {
+			why = fmt.Sprintf("%s option %q was removed", encoding, opt)
+		} else {
+			why = fmt.Sprintf("%s option %q was added", encoding, opt)
+		}
+	}
+	return res, why
+}
+
+// protobufComparer is a TagComparer for the tags in Go code generated from protocol buffer definitions,
+// such as
+//
+//	protobuf:"bytes,1,opt,name=user_id,json=userId,proto3"
+//
+// It treats a change in the wire type, field number, cardinality, name, or JSON name as Major,
+// a change in any other option as Patchlevel,
+// and reordering the options as no change.
+type protobufComparer struct{}
+
+// protobufMajor gives, in order, the parts of a protobuf tag that determine the encoding,
+// and how to describe them.
+var protobufMajor = []struct{ key, desc string }{
+	{key: "wiretype", desc: "wire type"},
+	{key: "number", desc: "field number"},
+	{key: "cardinality", desc: "cardinality"},
+	{key: "name", desc: "name"},
+	{key: "json", desc: "JSON name"},
+}
+
+func (protobufComparer) CompareTag(field, older, newer string) (ResultCode, string) {
+	switch {
+	case older == "":
+		return Major, "field is now encoded in protobuf"
+	case newer == "":
+		return Major, "field is no longer encoded in protobuf"
+	}
+
+	opts, newOpts := parseProtobufTag(older), parseProtobufTag(newer)
+	for _, m := range protobufMajor {
+		if opts[m.key] != newOpts[m.key] {
+			return Major, fmt.Sprintf("protobuf %s changed from %q to %q", m.desc, opts[m.key], newOpts[m.key])
+		}
+	}
+	for _, key := range sortedKeys(opts, newOpts) {
+		val, ok := opts[key]
+		newVal, newOK := newOpts[key]
+		if ok != newOK || val != newVal {
+			return Patchlevel, fmt.Sprintf("protobuf option %s changed", key)
+		}
+	}
+	return None, ""
+}
+
+// parseProtobufTag parses the value of a protobuf tag
+// into a map from option names to values.
+// The leading wire type, field number, and cardinality are keyed as
+// "wiretype",
+// "number",
+// and "cardinality".
+// Options without values,
+// such as "proto3",
+// map to "".
+func parseProtobufTag(val string) map[string]string {
+	res := make(map[string]string)
+	for i, part := range strings.Split(val, ",") {
+		if i < 3 {
+			res[protobufMajor[i].key] = part
+			continue
+		}
+		k, v, _ := strings.Cut(part, "=")
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,152 @@
+package modver
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCompareStructTags(t *testing.T) {
+	cases := []struct {
+		older, newer string
+		want         ResultCode
+		wantKind     ChangeKind
+		wantMsg      string
+	}{{
+		older: `json:"a"`, newer: `json:"a,omitempty"`,
+		want: Patchlevel, wantKind: TagEncodingChanged,
+		wantMsg: `JSON option "omitempty" was added`,
+	}, {
+		older: `json:"a,omitempty"`, newer: `json:"a"`,
+		want: Patchlevel, wantKind: TagEncodingChanged,
+		wantMsg: `JSON option "omitempty" was removed`,
+	}, {
+		older: `json:"a,omitempty"`, newer: `json:"b,omitempty"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `JSON name changed from "a" to "b"`,
+	}, {
+		older: `json:"a"`, newer: `json:"-"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: "field is no longer encoded in JSON",
+	}, {
+		older: `json:"-"`, newer: `json:"-,"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `field is now encoded in JSON as "-"`,
+	}, {
+		older: `json:"a,omitempty"`, newer: `json:"a,string,omitzero"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `JSON option "string" was added`,
+	}, {
+		older: `json:"a,omitempty,string"`, newer: `json:"a,string,omitempty"`,
+		want: None,
+	}, {
+		older: "", newer: `json:"Field"`,
+		want: None,
+	}, {
+		older: "", newer: `json:",omitempty"`,
+		want: Patchlevel, wantKind: TagEncodingChanged,
+	}, {
+		older: `yaml:"field"`, newer: "",
+		want: None,
+	}, {
+		older: `yaml:"field"`, newer: `yaml:",inline"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `YAML option "inline" was added`,
+	}, {
+		older: `xml:"a"`, newer: `xml:"a,attr"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `XML option "attr" was added`,
+	}, {
+		older: `xml:"a,attr,omitempty"`, newer: `xml:"a,omitempty,attr"`,
+		want: None,
+	}, {
+		older: `protobuf:"varint,1,opt,name=a,proto3"`, newer: `protobuf:"varint,2,opt,name=a,proto3"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `protobuf field number changed from "1" to "2"`,
+	}, {
+		older: `protobuf:"varint,1,opt,name=a,proto3"`, newer: `protobuf:"bytes,1,opt,name=a,proto3"`,
+		want: Major, wantKind: TagEncodingChanged,
+		wantMsg: `protobuf wire type changed from "varint" to "bytes"`,
+	}, {
+		older: `protobuf:"varint,1,opt,name=a,proto3"`, newer: `protobuf:"varint,1,opt,name=a"`,
+		want: Patchlevel, wantKind: TagEncodingChanged,
+		wantMsg: "protobuf option proto3 changed",
+	}, {
+		older: `protobuf:"varint,1,opt,name=a,json=a,proto3"`, newer: `protobuf:"varint,1,opt,proto3,json=a,name=a"`,
+		want: None,
+	}, {
+		older: `json:"a"`, newer: `json:"a,omitempty" foo:"bar"`,
+		want: Minor, wantKind: TagAdded,
+	}, {
+		older: `json:"a" foo:"bar"`, newer: `json:"a,omitempty" foo:"baz"`,
+		want: Major, wantKind: TagValueChanged,
+	}}
+
+	tcs := defaultTagComparers()
+	for _, tc := range cases {
+		res := compareStructTags("Field", tc.older, tc.newer, tcs)
+		if got := res.Code(); got != tc.want {
+			t.Errorf("%q -> %q: got %s, want %s", tc.older, tc.newer, got, tc.want)
+			continue
+		}
+		if tc.want == None {
+			continue
+		}
+		changes := res.Changes()
+		if len(changes) == 0 {
+			t.Errorf("%q -> %q: no changes", tc.older, tc.newer)
+			continue
+		}
+		if got := changes[0].Kind; got != tc.wantKind {
+			t.Errorf("%q -> %q: got kind %s, want %s", tc.older, tc.newer, got, tc.wantKind)
+		}
+		if tc.wantMsg != "" && !strings.Contains(res.String(), tc.wantMsg) {
+			t.Errorf("%q -> %q: got %q, want it to contain %q", tc.older, tc.newer, res, tc.wantMsg)
+		}
+	}
+}
+
+func TestWithTagComparer(t *testing.T) {
+	// A custom TagComparer for the foo key,
+	// which ignores case.
+	foo := TagComparerFunc(func(field, older, newer string) (ResultCode, string) {
+		if strings.EqualFold(older, newer) {
+			return None, ""
+		}
+		return Major, "foo value changed"
+	})
+
+	// The levels of these cases without options are checked by TestCompare.
+	cases := []struct {
+		dir, name string
+		opts      []Option
+		want      ResultCode
+	}{{
+		dir: "major", name: "jsondash",
+		opts: []Option{WithTagComparer("json", nil)},
+		want: Major,
+	}, {
+		dir: "none", name: "reorderoptions",
+		opts: []Option{WithTagComparer("json", nil)},
+		want: Major,
+	}, {
+		dir: "patchlevel", name: "omitempty",
+		opts: []Option{WithTagComparer("xml", TagComparerFunc(func(field, older, newer string) (ResultCode, string) {
+			return None, ""
+		}))},
+		want: Patchlevel,
+	}, {
+		dir: "major", name: "tagcase",
+		opts: []Option{WithTagComparer("foo", foo)},
+		want: None,
+	}}
+
+	for _, tc := range cases {
+		compareCase(t, tc.dir, tc.name, func(t *testing.T, olders, newers []*packages.Package) {
+			if got := CompareAll(olders, newers, tc.opts...).Code(); got != tc.want {
+				t.Errorf("with %d option(s): got %s, want %s", len(tc.opts), got, tc.want)
+			}
+		})
+	}
+}
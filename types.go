@@ -3,6 +3,8 @@ package modver
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/token"
 	"go/types"
 	"reflect"
 	"regexp"
@@ -16,15 +18,52 @@ type (
 		stack       []typePair
 		cache       map[typePair]Result
 		identicache map[typePair]bool
+
+		// For reporting the positions of older and newer objects in Changes.
+		olderFset, newerFset *token.FileSet
+
+		// The older and newer packages being compared, keyed by package path.
+		older, newer map[string]*packages.Package
+
+		// The public surfaces of the older and newer packages.
+		// See publicSurface.
+		olderSurface, newerSurface surface
+
+		// The constants in enum-like blocks of each package.
+		// See isEnumConst.
+		enumConsts map[*packages.Package]map[types.Object]bool
+
+		// The profile, and levels set by Options that override it.
+		// See level.
+		profile Profile
+		levels  map[ChangeKind]ResultCode
+
+		// The roles of the interfaces in the older packages,
+		// and roles set by Options that override them.
+		// See interfaceRole.
+		olderRoles, interfaceRoleOverrides map[typeKey]InterfaceRole
+
+		// The TagComparers for struct tag keys.
+		// See compareStructs.
+		tagComparers map[string]TagComparer
 	}
 	typePair struct{ a, b types.Type }
 )
 
-func newComparer() *comparer {
-	return &comparer{
+func newComparer(opts ...Option) *comparer {
+	c := &comparer{
 		cache:       make(map[typePair]Result),
 		identicache: make(map[typePair]bool),
+		enumConsts:  make(map[*packages.Package]map[types.Object]bool),
+		levels:      make(map[ChangeKind]ResultCode),
+
+		interfaceRoleOverrides: make(map[typeKey]InterfaceRole),
+		tagComparers:           defaultTagComparers(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 func (c *comparer) compareTypes(older, newer types.Type) (res Result) {
@@ -50,83 +89,83 @@ func (c *comparer) compareTypes(older, newer types.Type) (res Result) {
 	case *types.Array:
 		if newer, ok := newer.(*types.Array); ok {
 			if res = c.compareTypes(older.Elem(), newer.Elem()); res.Code() != None {
-				return rwrapf(res, "%s went from array of %s to array of %s", older, older.Elem(), newer.Elem())
+				return c.wrapf(res, ElemChanged, older.Elem(), newer.Elem(), "%s went from array of %s to array of %s", older, older.Elem(), newer.Elem())
 			}
 			if older.Len() != newer.Len() {
-				return rwrapf(Major, "%s went from length %d array to length %d", older, older.Len(), newer.Len())
+				return c.wrapf(Major, ArrayLenChanged, older, newer, "%s went from length %d array to length %d", older, older.Len(), newer.Len())
 			}
 			return None
 		}
-		return rwrapf(Major, "%s went from array to non-array", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from array to non-array", older)
 
 	case *types.Chan:
 		if newer, ok := newer.(*types.Chan); ok {
 			if res = c.compareTypes(older.Elem(), newer.Elem()); res.Code() != None {
-				return rwrapf(res, "%s went from channel of %s to channel of %s", older, older.Elem(), newer.Elem())
+				return c.wrapf(res, ElemChanged, older.Elem(), newer.Elem(), "%s went from channel of %s to channel of %s", older, older.Elem(), newer.Elem())
 			}
 			if older.Dir() == newer.Dir() {
 				return None
 			}
 			if older.Dir() == types.SendRecv {
-				return rwrapf(Minor, "%s went from send/receive channel to %s", older, describeDirection(newer.Dir()))
+				return c.wrapf(c.level(ChanDirRestricted, Minor), ChanDirRestricted, older, newer, "%s went from send/receive channel to %s", older, describeDirection(newer.Dir()))
 			}
-			return rwrapf(Major, "%s went from %s channel to %s", older, describeDirection(older.Dir()), describeDirection(newer.Dir()))
+			return c.wrapf(Major, ChanDirChanged, older, newer, "%s went from %s channel to %s", older, describeDirection(older.Dir()), describeDirection(newer.Dir()))
 		}
-		return rwrapf(Major, "%s went from channel to non-channel", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from channel to non-channel", older)
 
 	case *types.Pointer:
 		if newer, ok := newer.(*types.Pointer); ok {
 			return c.compareTypes(older.Elem(), newer.Elem())
 		}
-		return rwrapf(Major, "%s went from pointer to non-pointer", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from pointer to non-pointer", older)
 
 	case *types.Named:
 		if newer, ok := newer.(*types.Named); ok {
 			return c.compareNamed(older, newer)
 		}
 		if older.TypeParams().Len() > 0 {
-			return rwrapf(Major, "%s went from generic named type to unnamed %s", older, newer)
+			return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from generic named type to unnamed %s", older, newer)
 		}
 		return c.compareTypes(older.Underlying(), newer)
 
 	case *types.Struct:
 		if newer, ok := newer.(*types.Struct); ok {
-			return c.compareStructs(older, newer)
+			return c.compareStructs(older, newer, nil)
 		}
-		return rwrapf(Major, "%s went from struct to non-struct", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from struct to non-struct", older)
 
 	case *types.Interface:
 		if newer, ok := newer.(*types.Interface); ok {
-			return c.compareInterfaces(older, newer)
+			return c.compareInterfaces(older, newer, nil)
 		}
-		return rwrapf(Major, "%s went from interface to non-interface", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from interface to non-interface", older)
 
 	case *types.Signature:
 		if newer, ok := newer.(*types.Signature); ok {
 			return c.compareSignatures(older, newer)
 		}
-		return rwrapf(Major, "%s went from function to non-function", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from function to non-function", older)
 
 	case *types.Map:
 		if newer, ok := newer.(*types.Map); ok {
 			kres := c.compareTypes(older.Key(), newer.Key())
 			vres := c.compareTypes(older.Elem(), newer.Elem())
 			if kres.Code() > vres.Code() {
-				return rwrapf(kres, "in the map-key type of %s", older)
+				return c.wrapf(kres, MapKeyChanged, older.Key(), newer.Key(), "in the map-key type of %s", older)
 			}
-			return rwrapf(vres, "in the map-value type of %s", older)
+			return c.wrapf(vres, MapElemChanged, older.Elem(), newer.Elem(), "in the map-value type of %s", older)
 		}
-		return rwrapf(Major, "%s went from map to non-map", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from map to non-map", older)
 
 	case *types.Slice:
 		if newer, ok := newer.(*types.Slice); ok {
 			return c.compareTypes(older.Elem(), newer.Elem())
 		}
-		return rwrapf(Major, "%s went from slice to non-slice", older)
+		return c.wrapf(Major, TypeKindChanged, older, newer, "%s went from slice to non-slice", older)
 
 	default:
 		if !c.assignableTo(newer, older) {
-			return rwrapf(Major, "%s is not assignable to %s", newer, older)
+			return c.wrapf(Major, NotAssignable, older, newer, "%s is not assignable to %s", newer, older)
 		}
 		return None
 	}
@@ -148,24 +187,46 @@ func describeDirection(dir types.ChanDir) string {
 func (c *comparer) compareNamed(older, newer *types.Named) Result {
 	res := c.compareTypeParamLists(older.TypeParams(), newer.TypeParams())
 
+	var moved bool
+
 	olderPkg, newerPkg := older.Obj().Pkg(), newer.Obj().Pkg()
 	if olderPkg != nil {
 		if newerPkg == nil {
-			return rwrapf(Major, "%s went from package %s to no package", older, olderPkg.Path())
+			return c.wrapf(Major, TypePackageChanged, older, newer, "%s went from package %s to no package", older, olderPkg.Path())
 		}
 		olderPkgPath, newerPkgPath := olderPkg.Path(), newerPkg.Path()
 		if olderPkgPath != newerPkgPath {
-			return rwrapf(Major, "%s went from package %s to package %s", older, olderPkgPath, newerPkgPath)
+			if !c.isForwarded(older, newer) {
+				return c.wrapf(Major, TypePackageChanged, older, newer, "%s went from package %s to package %s", older, olderPkgPath, newerPkgPath)
+			}
+			// The type moved,
+			// and the old package forwards its old name to the new one.
+			// Compare the types' shapes.
+			moved = true
 		}
 	} else if newerPkg != nil {
-		return rwrapf(Major, "%s went from no package to package %s", older, newerPkg.Path())
+		return c.wrapf(Major, TypePackageChanged, older, newer, "%s went from no package to package %s", older, newerPkg.Path())
 	}
 
-	if r := c.compareTypes(older.Underlying(), newer.Underlying()); r.Code() > res.Code() {
+	var r Result
+	switch ou := older.Underlying().(type) {
+	case *types.Interface:
+		if nu, ok := newer.Underlying().(*types.Interface); ok {
+			r = c.compareInterfaces(ou, nu, older)
+		}
+	case *types.Struct:
+		if nu, ok := newer.Underlying().(*types.Struct); ok {
+			r = c.compareStructs(ou, nu, older)
+		}
+	}
+	if r == nil {
+		r = c.compareTypes(older.Underlying(), newer.Underlying())
+	}
+	if r.Code() > res.Code() {
 		res = r
 	}
 
-	if w, ok := res.(wrapped); ok {
+	if w, ok := res.(wrapped); ok && !moved {
 		var replaced bool
 		for i, arg := range w.whyargs {
 			if _, ok := arg.(types.Type); !ok {
@@ -180,19 +241,40 @@ func (c *comparer) compareNamed(older, newer *types.Named) Result {
 			}
 		}
 		if replaced {
+			if w.change.OldType == older.Underlying().String() {
+				w.change.OldPos, w.change.OldType = describe(c.olderFset, older)
+			}
+			if w.change.NewType == newer.Underlying().String() {
+				w.change.NewPos, w.change.NewType = describe(c.newerFset, newer)
+			}
 			return w
 		}
 	}
 
-	return rwrapf(res, "in type %s", older)
+	if moved {
+		return c.wrapf(res, NamedTypeChanged, older, newer, "in type %s, which moved to package %s", older, newerPkg.Path())
+	}
+	return c.wrapf(res, NamedTypeChanged, older, newer, "in type %s", older)
 }
 
-func (c *comparer) compareStructs(older, newer *types.Struct) Result {
+// compareStructs compares struct types older and newer.
+// If older is the underlying type of a named type,
+// named is that type,
+// otherwise nil.
+// Only the fields of a named type's struct use the TagComparers
+// (see WithTagComparer);
+// an unnamed struct type with any change in its tags is a different type.
+func (c *comparer) compareStructs(older, newer *types.Struct, named *types.Named) Result {
 	var (
 		olderMap = structMap(older)
 		newerMap = structMap(newer)
 	)
 
+	var tagComparers map[string]TagComparer
+	if named != nil {
+		tagComparers = c.tagComparers
+	}
+
 	var res Result = None
 
 	for i := 0; i < older.NumFields(); i++ {
@@ -203,12 +285,12 @@ func (c *comparer) compareStructs(older, newer *types.Struct) Result {
 		}
 		newFieldIndex, ok := newerMap[field.Name()]
 		if !ok {
-			return rwrapf(Major, "old struct field %s was removed from %s", field.Name(), older)
+			return c.wrapf(Major, FieldRemoved, field, nil, "old struct field %s was removed from %s", field.Name(), older)
 		}
 		newField := newer.Field(newFieldIndex)
 
 		if r := c.compareTypes(field.Type(), newField.Type()); r.Code() > res.Code() {
-			res = rwrapf(r, "struct field %s changed in %s", field.Name(), older)
+			res = c.wrapf(r, FieldChanged, field, newField, "struct field %s changed in %s", field.Name(), older)
 			if res.Code() == Major {
 				return res
 			}
@@ -218,63 +300,96 @@ func (c *comparer) compareStructs(older, newer *types.Struct) Result {
 			tag    = older.Tag(i)
 			newTag = newer.Tag(newFieldIndex)
 		)
-		if r := c.compareStructTags(tag, newTag); r.Code() == Major {
-			return rwrapf(r, "tag change in field %s of %s", field.Name(), older)
+		if r := compareStructTags(field.Name(), tag, newTag, tagComparers); r.Code() > res.Code() {
+			res = c.wrapf(r, FieldTagChanged, field, newField, "tag change in field %s of %s", field.Name(), older)
+			if res.Code() == Major {
+				return res
+			}
 		}
 	}
 
 	for i := 0; i < newer.NumFields(); i++ {
 		field := newer.Field(i)
-		if !ast.IsExported(field.Name()) {
-			// Changes in unexported struct fields don't count.
+		if _, ok := olderMap[field.Name()]; ok {
 			continue
 		}
-		oldFieldIndex, ok := olderMap[field.Name()]
-		if !ok {
-			return rwrapf(Minor, "struct field %s was added to %s", field.Name(), newer)
+		if ast.IsExported(field.Name()) {
+			return c.wrapf(c.level(FieldAdded, Minor), FieldAdded, nil, field, "struct field %s was added to %s", field.Name(), newer)
 		}
-		var (
-			oldTag = older.Tag(oldFieldIndex)
-			tag    = newer.Tag(i)
-		)
-		if res := c.compareStructTags(oldTag, tag); res.Code() == Minor {
-			return rwrapf(res, "tag change in field %s of %s", field.Name(), older)
+
+		// Changes in unexported struct fields don't count,
+		// except that adding one to a struct whose fields were all exported
+		// breaks unkeyed composite literals of it,
+		// which can no longer list all its fields.
+		if older.NumFields() > 0 && !anyUnexportedFields(older) {
+			if r := c.wrapf(c.level(FieldAdded, Patchlevel), FieldAdded, nil, field, "unexported struct field %s was added to %s, whose fields were all exported", field.Name(), newer); r.Code() > res.Code() {
+				res = r
+			}
 		}
 	}
 
-	if !c.identical(older, newer) {
-		return rwrapf(Patchlevel, "old and new versions of %s are not identical", older)
+	if res.Code() != None {
+		return res
+	}
+	if !c.identical(older, withOlderTags(older, newer, tagComparers)) {
+		return c.wrapf(Patchlevel, NotIdentical, older, newer, "old and new versions of %s are not identical", older)
 	}
 
 	return None
 }
 
-func (c *comparer) compareInterfaces(older, newer *types.Interface) Result {
+// withOlderTags gives newer with the tag of each field replaced by the tag of the corresponding field in older,
+// if the TagComparers in tcs find no difference between them.
+// Such tags count as identical.
+func withOlderTags(older, newer *types.Struct, tcs map[string]TagComparer) *types.Struct {
+	if len(tcs) == 0 || older.NumFields() != newer.NumFields() {
+		return newer
+	}
+	var (
+		fields = make([]*types.Var, newer.NumFields())
+		tags   = make([]string, newer.NumFields())
+	)
+	for i := range fields {
+		fields[i], tags[i] = newer.Field(i), newer.Tag(i)
+		if compareStructTags(fields[i].Name(), older.Tag(i), tags[i], tcs).Code() == None {
+			tags[i] = older.Tag(i)
+		}
+	}
+	return types.NewStruct(fields, tags)
+}
+
+// compareInterfaces compares interface types older and newer.
+// If older is the underlying type of a named type,
+// named is that type,
+// otherwise nil.
+func (c *comparer) compareInterfaces(older, newer *types.Interface, named *types.Named) Result {
 	var res Result = None
 
 	if c.implements(newer, older) {
 		if !c.implements(older, newer) {
 			switch {
 			case anyUnexportedMethods(older):
-				res = rwrapf(Minor, "new interface %s is a superset of older, with unexported methods", newer)
+				res = c.wrapf(Minor, SealedInterfaceMethodsAdded, older, newer, "new interface %s is a superset of older, with unexported methods", newer)
 			case anyInternalTypes(older):
-				res = rwrapf(Minor, "new interface %s is a superset of older, using internal types", newer)
+				res = c.wrapf(Minor, SealedInterfaceMethodsAdded, older, newer, "new interface %s is a superset of older, using internal types", newer)
+			case named != nil && c.interfaceRole(named) == ConsumerOnly:
+				res = c.wrapf(c.level(ConsumerOnlyMethodsAdded, Minor), ConsumerOnlyMethodsAdded, older, newer, "new interface %s is a superset of older, but clients only consume %s", newer, named.Obj().Name())
 			default:
-				res = rwrapf(Major, "new interface %s is a superset of older", newer)
+				res = c.wrapf(Major, InterfaceMethodsAdded, older, newer, "new interface %s is a superset of older", newer)
 			}
 		}
 	} else {
-		return rwrapf(Major, "new interface %s does not implement old", newer)
+		return c.wrapf(Major, InterfaceIncompatible, older, newer, "new interface %s does not implement old", newer)
 	}
 
 	if isNonEmptyMethodSet(older) {
 		if isNonEmptyMethodSet(newer) {
 			return res
 		}
-		return rwrap(Major, "new interface is a constraint, old one is not")
+		return c.wrapf(Major, ConstraintKindChanged, older, newer, "new interface is a constraint, old one is not")
 	}
 	if isNonEmptyMethodSet(newer) {
-		return rwrap(Major, "old interface is a constraint, new one is not")
+		return c.wrapf(Major, ConstraintKindChanged, older, newer, "old interface is a constraint, new one is not")
 	}
 
 	olderTerms, newerTerms := termsOf(older), termsOf(newer)
@@ -285,45 +400,45 @@ func (c *comparer) compareInterfaces(older, newer *types.Interface) Result {
 				if newer.IsComparable() {
 					return res
 				}
-				return rwrap(Minor, "constraint went from comparable to any")
+				return c.wrapf(Minor, ConstraintRelaxed, older, newer, "constraint went from comparable to any")
 			}
 			if newer.IsComparable() {
-				return rwrap(Major, "constraint went from any to comparable")
+				return c.wrapf(Major, ConstraintTightened, older, newer, "constraint went from any to comparable")
 			}
 		}
 		if older.IsComparable() {
 			if newer.IsComparable() {
-				return rwrap(Major, "constraint went from all to some comparable types")
+				return c.wrapf(Major, ConstraintTightened, older, newer, "constraint went from all to some comparable types")
 			}
-			return rwrap(Major, "constraint went from comparable to (some) non-comparable types")
+			return c.wrapf(Major, ConstraintTightened, older, newer, "constraint went from comparable to (some) non-comparable types")
 		}
 		if newer.IsComparable() {
-			return rwrap(Major, "constraint went from any to (some) comparable types")
+			return c.wrapf(Major, ConstraintTightened, older, newer, "constraint went from any to (some) comparable types")
 		}
 		return res
 	}
 	if len(newerTerms) == 0 {
 		if older.IsComparable() {
 			if newer.IsComparable() {
-				return rwrap(Minor, "constraint went from some to all comparable types")
+				return c.wrapf(Minor, ConstraintRelaxed, older, newer, "constraint went from some to all comparable types")
 			}
-			return rwrap(Minor, "constraint went from some comparable types to any")
+			return c.wrapf(Minor, ConstraintRelaxed, older, newer, "constraint went from some comparable types to any")
 		}
 		if newer.IsComparable() {
-			return rwrap(Major, "constraint went from (some) non-comparable types to comparable")
+			return c.wrapf(Major, ConstraintTightened, older, newer, "constraint went from (some) non-comparable types to comparable")
 		}
-		return rwrap(Major, "new constraint removes type union")
+		return c.wrapf(Major, ConstraintTightened, older, newer, "new constraint removes type union")
 	}
 	if c.termListSubset(olderTerms, newerTerms) {
 		if c.termListSubset(newerTerms, olderTerms) {
 			return res
 		}
-		return rwrapf(Minor, "older constraint type union is a subset of newer (constraint has relaxed)")
+		return c.wrapf(Minor, ConstraintRelaxed, older, newer, "older constraint type union is a subset of newer (constraint has relaxed)")
 	}
 	if c.termListSubset(newerTerms, olderTerms) {
-		return rwrapf(Major, "newer constraint type union is a subset of older (constraint has tightened)")
+		return c.wrapf(Major, ConstraintTightened, older, newer, "newer constraint type union is a subset of older (constraint has tightened)")
 	}
-	return rwrapf(Major, "constraint type unions differ")
+	return c.wrapf(Major, ConstraintTightened, older, newer, "constraint type unions differ")
 }
 
 func anyUnexportedMethods(intf *types.Interface) bool {
@@ -422,12 +537,12 @@ func (c *comparer) compareSignatures(older, newer *types.Signature) Result {
 		resultsRes    = c.compareTuples(older.Results(), newer.Results(), false)
 	)
 
-	res := rwrapf(typeParamsRes, "in type parameters of %s", older)
+	res := c.wrapf(typeParamsRes, TypeParamsChanged, older, newer, "in type parameters of %s", older)
 	if paramsRes.Code() > res.Code() {
-		res = rwrapf(paramsRes, "in parameters of %s", older)
+		res = c.wrapf(paramsRes, ParamsChanged, older, newer, "in parameters of %s", older)
 	}
 	if resultsRes.Code() > res.Code() {
-		res = rwrapf(resultsRes, "in results of %s", older)
+		res = c.wrapf(resultsRes, ResultsChanged, older, newer, "in results of %s", older)
 	}
 	return res
 }
@@ -438,7 +553,7 @@ func (c *comparer) compareTuples(older, newer *types.Tuple, variadicCheck bool)
 	maybeVariadic := variadicCheck && (la+1 == lb)
 
 	if la != lb && !maybeVariadic {
-		return rwrapf(Major, "%d param(s) to %d", la, lb)
+		return c.wrapf(Major, ParamCountChanged, older, newer, "%d param(s) to %d", la, lb)
 	}
 
 	var res Result = None
@@ -454,20 +569,20 @@ func (c *comparer) compareTuples(older, newer *types.Tuple, variadicCheck bool)
 	}
 
 	if res.Code() < Minor && maybeVariadic {
-		return rwrap(Minor, "added optional parameters")
+		return c.wrapf(c.level(OptionalParamsAdded, Minor), OptionalParamsAdded, older, newer, "added optional parameters")
 	}
 	return res
 }
 
 func (c *comparer) compareTypeParamLists(older, newer *types.TypeParamList) Result {
 	if older.Len() != newer.Len() {
-		return rwrapf(Major, "went from %d type parameter(s) to %d", older.Len(), newer.Len())
+		return wrapk(Major, TypeParamCountChanged, "went from %d type parameter(s) to %d", older.Len(), newer.Len())
 	}
 
 	var res Result = None
 
 	for i := 0; i < older.Len(); i++ {
-		thisRes := c.compareTypes(older.At(i).Constraint(), newer.At(i).Constraint())
+		thisRes := c.compareTypes(constraintIntf(older.At(i).Constraint()), constraintIntf(newer.At(i).Constraint()))
 		if thisRes.Code() > res.Code() {
 			res = thisRes
 			if res.Code() == Major {
@@ -479,29 +594,59 @@ func (c *comparer) compareTypeParamLists(older, newer *types.TypeParamList) Resu
 	return res
 }
 
-func (c *comparer) compareStructTags(a, b string) Result {
+// constraintIntf gives the constraint typ as an interface
+// if it is the predeclared comparable,
+// so that it compares with other constraints as an interface
+// (which admits only comparable types)
+// and not as a named type.
+// Otherwise it gives typ.
+func constraintIntf(typ types.Type) types.Type {
+	if typ == types.Universe.Lookup("comparable").Type() {
+		return typ.Underlying()
+	}
+	return typ
+}
+
+// compareStructTags compares a and b,
+// the older and newer tags of the struct field named field.
+// The values of keys with a TagComparer in tcs are compared with it
+// (see WithTagComparer).
+// For other keys,
+// a changed or removed value is Major,
+// and an added one is Minor.
+func compareStructTags(field, a, b string, tcs map[string]TagComparer) Result {
 	if a == b {
 		return None
 	}
 	var (
-		amap = tagMap(a)
-		bmap = tagMap(b)
+		amap        = tagMap(a)
+		bmap        = tagMap(b)
+		res  Result = None
 	)
-	for k, av := range amap {
-		if bv, ok := bmap[k]; ok {
-			if av != bv {
-				return rwrapf(Major, `struct tag changed the value for key "%s" from "%s" to "%s"`, k, av, bv)
-			}
-		} else {
-			return rwrapf(Major, "struct tag %s was removed", k)
+	for _, k := range sortedKeys(amap, bmap) {
+		var (
+			av, aok = amap[k]
+			bv, bok = bmap[k]
+			r       Result
+		)
+		switch tc := tcs[k]; {
+		case av == bv && aok == bok:
+			continue
+		case tc != nil:
+			code, why := tc.CompareTag(field, av, bv)
+			r = wrapk(code, TagEncodingChanged, "struct tag %s: %s", k, why)
+		case !bok:
+			r = wrapk(Major, TagRemoved, "struct tag %s was removed", k)
+		case !aok:
+			r = wrapk(Minor, TagAdded, "struct tag %s was added", k)
+		default:
+			r = wrapk(Major, TagValueChanged, `struct tag changed the value for key "%s" from "%s" to "%s"`, k, av, bv)
 		}
-	}
-	for k := range bmap {
-		if _, ok := amap[k]; !ok {
-			return rwrapf(Minor, "struct tag %s was added", k)
+		if r.Code() > res.Code() {
+			res = r
 		}
 	}
-	return None
+	return res
 }
 
 // https://golang.org/ref/spec#Assignability
@@ -515,6 +660,12 @@ func (c *comparer) assignableTo(v, t types.Type) bool {
 		return true
 	}
 
+	// The remaining rules are for types other than type parameters,
+	// whose underlying types are their constraints.
+	if isTypeParam(v) || isTypeParam(t) {
+		return false
+	}
+
 	// "x's type V and T have identical underlying types
 	// and at least one of V or T is not a defined type"
 	uv, ut := v.Underlying(), t.Underlying()
@@ -541,6 +692,11 @@ func (c *comparer) assignableTo(v, t types.Type) bool {
 	return c.assignableBasic(v, t, uv, ut)
 }
 
+func isTypeParam(typ types.Type) bool {
+	_, ok := typ.(*types.TypeParam)
+	return ok
+}
+
 func (c *comparer) assignableChan(v, t, uv, ut types.Type) bool {
 	// "x is a bidirectional channel value,
 	// T is a channel type,
@@ -587,7 +743,7 @@ func (c *comparer) assignableBasic(v, t, uv, ut types.Type) bool {
 	// "x is an untyped constant representable by a value of type T"
 	switch b.Kind() {
 	case types.UntypedBool, types.UntypedInt, types.UntypedRune, types.UntypedFloat, types.UntypedComplex, types.UntypedString:
-		return representable(b, t)
+		return representable(b, nil, t)
 	}
 
 	return false
@@ -618,13 +774,22 @@ func (c *comparer) samePackage(a, b *types.Package) bool {
 }
 
 // https://golang.org/ref/spec#Representability
-// TODO: Add range checking of literals.
-func representable(x *types.Basic, t types.Type) bool {
+//
+// If val is non-nil,
+// it is the value of an untyped constant of type x,
+// and the result tells whether that value is representable by a value of type t.
+// Otherwise the result tells whether any value of type x may be,
+// according to the kinds of the types alone.
+func representable(x *types.Basic, val constant.Value, t types.Type) bool {
 	tb, ok := t.Underlying().(*types.Basic)
 	if !ok {
 		return false
 	}
 
+	if val != nil {
+		return representableValue(val, tb)
+	}
+
 	switch x.Kind() {
 	case types.UntypedBool:
 		return (tb.Info() & types.IsBoolean) == types.IsBoolean
@@ -677,31 +842,50 @@ func makeTopObjs(pkg *packages.Package) map[string]types.Object {
 	res := make(map[string]types.Object)
 	for _, file := range pkg.Syntax {
 		for _, decl := range file.Decls {
+			// Blank declarations are left out,
+			// since there is no telling which older one corresponds to which newer one.
+			// They are compared together in compareBlanks.
 			switch decl := decl.(type) {
 			case *ast.GenDecl:
 				for _, spec := range decl.Specs {
 					switch spec := spec.(type) {
 					case *ast.ValueSpec:
 						for _, name := range spec.Names {
-							res[name.Name] = pkg.TypesInfo.Defs[name]
+							if name.Name != "_" {
+								res[name.Name] = pkg.TypesInfo.Defs[name]
+							}
 						}
 
 					case *ast.TypeSpec:
-						res[spec.Name.Name] = pkg.TypesInfo.Defs[spec.Name]
+						if spec.Name.Name != "_" {
+							res[spec.Name.Name] = pkg.TypesInfo.Defs[spec.Name]
+						}
 					}
 				}
 
 			case *ast.FuncDecl:
-				// If decl is a method, qualify the name with the receiver type.
-				name := decl.Name.Name
-				if decl.Recv != nil && len(decl.Recv.List) > 0 {
-					recv := decl.Recv.List[0].Type
-					if info := pkg.TypesInfo.Types[recv]; info.Type != nil {
-						name = types.TypeString(info.Type, types.RelativeTo(pkg.Types)) + "." + name
+				if decl.Name.Name == "_" {
+					continue
+				}
+
+				// If decl is a method, qualify the name with the receiver type,
+				// as in T.M or *T.M.
+				// The receiver's type parameters are left out,
+				// since renaming them makes no difference.
+				var (
+					name = decl.Name.Name
+					obj  = pkg.TypesInfo.Defs[decl.Name]
+				)
+				if fn, ok := obj.(*types.Func); ok {
+					if named := recvNamed(fn); named != nil {
+						name = named.Obj().Name() + "." + name
+						if _, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+							name = "*" + name
+						}
 					}
 				}
 
-				res[name] = pkg.TypesInfo.Defs[decl.Name]
+				res[name] = obj
 			}
 		}
 	}
@@ -709,6 +893,15 @@ func makeTopObjs(pkg *packages.Package) map[string]types.Object {
 	return res
 }
 
+func anyUnexportedFields(t *types.Struct) bool {
+	for i := 0; i < t.NumFields(); i++ {
+		if !t.Field(i).Exported() {
+			return true
+		}
+	}
+	return false
+}
+
 func structMap(t *types.Struct) map[string]int {
 	result := make(map[string]int)
 	for i := 0; i < t.NumFields(); i++ {
@@ -0,0 +1,62 @@
+package modver
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/types"
+	"regexp"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// compareVarInits compares the initializers of obj and newObj,
+// top-level objects in the package at pkgPath,
+// if they are both exported variables.
+// A variable like DefaultTimeout supplies a default to every caller that does not override it,
+// so a change in its initializer changes their behavior.
+// Changes to comments and formatting do not count.
+func (c *comparer) compareVarInits(pkgPath string, obj, newObj types.Object) Result {
+	if _, ok := obj.(*types.Var); !ok || !obj.Exported() {
+		return None
+	}
+	if _, ok := newObj.(*types.Var); !ok {
+		return None
+	}
+
+	oldPkg, newPkg := c.older[pkgPath], c.newer[pkgPath]
+	if oldPkg == nil || newPkg == nil {
+		return None
+	}
+	oldFP, ok := implFingerprint(oldPkg, obj)
+	if !ok {
+		return None
+	}
+	newFP, ok := implFingerprint(newPkg, newObj)
+	if !ok || oldFP == newFP {
+		return None
+	}
+
+	return c.wrapf(c.level(VarInitChanged, Patchlevel), VarInitChanged, obj, newObj, "default changed: initializer of variable %s changed from %s to %s", obj.Name(), varInitString(oldPkg, obj), varInitString(newPkg, newObj))
+}
+
+var newlineIndent = regexp.MustCompile(`\n[ \t]*`)
+
+// varInitString gives the source of the initializer of obj,
+// a top-level variable in pkg,
+// on a single line.
+func varInitString(pkg *packages.Package, obj types.Object) string {
+	spec, ok := findDeclNode(pkg, obj).(*ast.ValueSpec)
+	if !ok {
+		return "(unknown)"
+	}
+	expr := varInit(spec, obj)
+	if expr == nil {
+		return "(zero value)"
+	}
+	buf := new(bytes.Buffer)
+	if err := printer.Fprint(buf, pkg.Fset, expr); err != nil {
+		return "(unknown)"
+	}
+	return newlineIndent.ReplaceAllString(buf.String(), " ")
+}
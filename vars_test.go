@@ -0,0 +1,17 @@
+package modver
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestVarInitLevels(t *testing.T) {
+	for _, want := range []ResultCode{Minor, None} {
+		compareCase(t, "patchlevel", "chdefault", func(t *testing.T, olders, newers []*packages.Package) {
+			if got := CompareAll(olders, newers, WithVarInitLevel(want)).Code(); got != want {
+				t.Errorf("with WithVarInitLevel(%s), got %s, want %s", want, got, want)
+			}
+		})
+	}
+}